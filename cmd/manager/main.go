@@ -30,6 +30,7 @@ import (
 	"github.com/shipwright-io/build/pkg/apis"
 	"github.com/shipwright-io/build/pkg/controller"
 	buildMetrics "github.com/shipwright-io/build/pkg/metrics"
+	"github.com/shipwright-io/build/pkg/webhook"
 	"github.com/shipwright-io/build/version"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
@@ -140,6 +141,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup the receiver of git webhooks
+	if err := webhook.Add(ctx, c, mgr); err != nil {
+		ctxlog.Error(ctx, err, "")
+		os.Exit(1)
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg, namespace)
 	buildMetrics.InitPrometheus(c)
@@ -19,10 +19,12 @@ CRDS=(
     deploy/role_binding.yaml
     deploy/service_account.yaml
     deploy/operator.yaml
+    deploy/webhook_service.yaml
     deploy/crds/build.dev_buildstrategies_crd.yaml
     deploy/crds/build.dev_clusterbuildstrategies_crd.yaml
     deploy/crds/build.dev_builds_crd.yaml
     deploy/crds/build.dev_buildruns_crd.yaml
+    deploy/crds/build.dev_clusterbuildrunquotas_crd.yaml
     # cluster scope build strategies
     samples/buildstrategy/buildpacks-v3/buildstrategy_buildpacks-v3-heroku_cr.yaml
     samples/buildstrategy/buildpacks-v3/buildstrategy_buildpacks-v3_cr.yaml
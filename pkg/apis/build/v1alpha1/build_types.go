@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -60,6 +61,39 @@ type BuildSpec struct {
 	// +optional
 	// +kubebuilder:validation:Format=duration
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Triggers defines the events that create BuildRuns of the Build automatically.
+	// +optional
+	Triggers *Triggers `json:"triggers,omitempty"`
+
+	// Schedule defines a cron schedule on which BuildRuns of the Build are created.
+	// +optional
+	Schedule *Schedule `json:"schedule,omitempty"`
+
+	// Retries defines how often a BuildRun retries a failed TaskRun.
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
+
+	// Retention defines how long completed BuildRuns of the Build are kept.
+	// +optional
+	Retention *BuildRetention `json:"retention,omitempty"`
+
+	// ConcurrencyPolicy defines how BuildRuns of the Build that run at the same time are
+	// handled, they all run by default.
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Platforms are the platforms like linux/amd64 that the image is built for, every
+	// platform is built on a node of the platform. The output image is then an image
+	// index of the images of all platforms.
+	// +optional
+	Platforms []string `json:"platforms,omitempty"`
+
+	// VulnerabilityPolicy scans the pushed output image for vulnerabilities, and fails the
+	// BuildRun if it has vulnerabilities of the severity of the policy or a higher one. It
+	// overrides the policy of the build strategy.
+	// +optional
+	VulnerabilityPolicy *VulnerabilityPolicy `json:"vulnerabilityPolicy,omitempty"`
 }
 
 // Image refers to an container image with credentials
@@ -71,6 +105,132 @@ type Image struct {
 	// credentials to push the image to the registry
 	// +optional
 	SecretRef *corev1.LocalObjectReference `json:"credentials,omitempty"`
+
+	// Tags are further tags of the output image in its repository, they are templates
+	// like {{.Revision.ShortSHA}} or {{.BuildRun.Name}}. The tags are only used for
+	// the output image.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Signing signs the output image with a key after it is pushed, the signature is pushed
+	// next to the image. Signing is only used for the output image.
+	// +optional
+	Signing *ImageSigning `json:"signing,omitempty"`
+
+	// SBOM generates a software bill of materials of the output image after it is pushed, the
+	// SBOM is attached to the image in its registry. SBOM is only used for the output image.
+	// +optional
+	SBOM *ImageSBOM `json:"sbom,omitempty"`
+
+	// Provenance pushes an attestation of the provenance of the output image after it is pushed,
+	// the attestation describes the source, the strategy and the parameters that built the
+	// image. Provenance is only used for the output image.
+	// +optional
+	Provenance *bool `json:"provenance,omitempty"`
+}
+
+// Keys in the secret of the signing of an output image
+const (
+	// SigningSecretKey is the key in the secret of the signing that holds the cosign private key
+	SigningSecretKey = "cosign.key"
+
+	// SigningSecretPasswordKey is the key in the secret of the signing that holds the password
+	// of the private key, which can be empty
+	SigningSecretPasswordKey = "cosign.password"
+)
+
+// ImageSigning defines the key that the output image is signed with
+type ImageSigning struct {
+	// SecretRef refers to the secret that holds the cosign private key in its key "cosign.key",
+	// and the password of the key in its key "cosign.password".
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+// SBOMFormat is the format of a software bill of materials
+// +kubebuilder:validation:Enum=SPDX;CycloneDX
+type SBOMFormat string
+
+const (
+	// SBOMFormatSPDX is the SPDX format in JSON
+	SBOMFormatSPDX SBOMFormat = "SPDX"
+
+	// SBOMFormatCycloneDX is the CycloneDX format in JSON
+	SBOMFormatCycloneDX SBOMFormat = "CycloneDX"
+)
+
+// SBOMScan is what the software bill of materials of an image describes
+// +kubebuilder:validation:Enum=Image;Source
+type SBOMScan string
+
+const (
+	// SBOMScanImage describes the packages in the pushed image
+	SBOMScanImage SBOMScan = "Image"
+
+	// SBOMScanSource describes the dependencies in the source of the Build
+	SBOMScanSource SBOMScan = "Source"
+)
+
+// ImageSBOM defines the software bill of materials that is attached to the output image
+type ImageSBOM struct {
+	// Format is the format of the SBOM, SPDX by default.
+	// +optional
+	Format SBOMFormat `json:"format,omitempty"`
+
+	// Scan is what the SBOM describes, the pushed image by default, or the source in the
+	// context directory of the Build.
+	// +optional
+	Scan SBOMScan `json:"scan,omitempty"`
+}
+
+// ConcurrencyPolicy defines how a BuildRun of a Build is handled if other BuildRuns of the Build
+// are running
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace;Queue
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow runs the BuildRuns of a Build at the same time
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "Allow"
+
+	// ConcurrencyPolicyForbid fails a BuildRun if another BuildRun of the Build is running
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
+
+	// ConcurrencyPolicyReplace cancels the running BuildRuns of the Build when a BuildRun starts
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+
+	// ConcurrencyPolicyQueue keeps a BuildRun pending until the older BuildRuns of the Build completed
+	ConcurrencyPolicyQueue ConcurrencyPolicy = "Queue"
+)
+
+// Retries defines how often a failed TaskRun of a BuildRun is retried with a new TaskRun
+type Retries struct {
+	// Max is the maximum number of retries, a BuildRun has at most Max+1 TaskRuns.
+	// +kubebuilder:validation:Minimum=0
+	Max int32 `json:"max"`
+
+	// Backoff is the time to wait before the first retry, it doubles for every further
+	// retry. A retry starts immediately by default.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// BuildRetention defines which completed BuildRuns of a Build are kept, older ones are deleted
+// together with their TaskRuns and pods. BuildRuns that did not complete are never deleted.
+type BuildRetention struct {
+	// SucceededLimit is the number of the latest succeeded BuildRuns that are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SucceededLimit *int32 `json:"succeededLimit,omitempty"`
+
+	// FailedLimit is the number of the latest failed BuildRuns that are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedLimit *int32 `json:"failedLimit,omitempty"`
+
+	// TTLAfterFinished is the time after its completion at which a BuildRun is deleted.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	TTLAfterFinished *metav1.Duration `json:"ttlAfterFinished,omitempty"`
 }
 
 // Runtime represents the runtime-image, created using parts of builder-image, and a different
@@ -118,15 +278,132 @@ type User struct {
 	Group string `json:"group,omitempty"`
 }
 
+// Condition types of a Build
+const (
+	// BuildConditionSecretsResolved reports whether all secrets that the Build references exist
+	BuildConditionSecretsResolved corev1alpha1.ConditionType = "SecretsResolved"
+
+	// BuildConditionStrategyResolved reports whether the build strategy of the Build exists and is valid
+	BuildConditionStrategyResolved corev1alpha1.ConditionType = "StrategyResolved"
+
+	// BuildConditionParametersValid reports whether the parameters of the Build match the parameters
+	// that the build strategy declares
+	BuildConditionParametersValid corev1alpha1.ConditionType = "ParametersValid"
+
+	// BuildConditionRuntimeValid reports whether the runtime-image settings of the Build are valid
+	BuildConditionRuntimeValid corev1alpha1.ConditionType = "RuntimeValid"
+
+	// BuildConditionScheduleValid reports whether the schedule of the Build is a valid cron expression
+	BuildConditionScheduleValid corev1alpha1.ConditionType = "ScheduleValid"
+
+	// BuildConditionPlatformsValid reports whether the platforms of the Build are valid
+	BuildConditionPlatformsValid corev1alpha1.ConditionType = "PlatformsValid"
+
+	// BuildConditionTagsValid reports whether the tag templates of the output of the Build result
+	// in valid tags
+	BuildConditionTagsValid corev1alpha1.ConditionType = "TagsValid"
+
+	// BuildConditionVulnerabilityPolicyValid reports whether the vulnerability policy of the Build
+	// or its strategy can be enforced
+	BuildConditionVulnerabilityPolicyValid corev1alpha1.ConditionType = "VulnerabilityPolicyValid"
+
+	// BuildConditionReady reports whether all other conditions of the Build are True, so that
+	// BuildRuns can use it
+	BuildConditionReady = corev1alpha1.ConditionReady
+)
+
+// Reasons of the conditions of a Build
+const (
+	// BuildReasonSucceeded indicates that a validation of the Build succeeded
+	BuildReasonSucceeded = "Succeeded"
+
+	// BuildReasonSecretNotFound indicates that a secret referenced by the Build does not exist
+	BuildReasonSecretNotFound = "SecretNotFound"
+
+	// BuildReasonStrategyNotFound indicates that the build strategy of the Build does not exist
+	BuildReasonStrategyNotFound = "StrategyNotFound"
+
+	// BuildReasonUnknownStrategyKind indicates that the kind of the build strategy of the Build is not supported
+	BuildReasonUnknownStrategyKind = "UnknownStrategyKind"
+
+	// BuildReasonStrategyInvalid indicates that the build strategy references unknown placeholders,
+	// or declares parameters that none of its steps references
+	BuildReasonStrategyInvalid = "StrategyInvalid"
+
+	// BuildReasonStrategyNotResolved indicates that the parameters could not be validated, because
+	// the build strategy is not resolved
+	BuildReasonStrategyNotResolved = "StrategyNotResolved"
+
+	// BuildReasonParametersInvalid indicates that the parameters of the Build do not match the
+	// parameters that the build strategy declares
+	BuildReasonParametersInvalid = "ParametersInvalid"
+
+	// BuildReasonRuntimeInvalid indicates that the runtime-image settings of the Build are invalid
+	BuildReasonRuntimeInvalid = "RuntimeInvalid"
+
+	// BuildReasonScheduleInvalid indicates that the cron expression of the schedule of the Build is invalid
+	BuildReasonScheduleInvalid = "ScheduleInvalid"
+
+	// BuildReasonPlatformsInvalid indicates that a platform of the Build is invalid or duplicated
+	BuildReasonPlatformsInvalid = "PlatformsInvalid"
+
+	// BuildReasonTagsInvalid indicates that a tag template of the output of the Build is invalid
+	// or results in an invalid tag
+	BuildReasonTagsInvalid = "TagsInvalid"
+
+	// BuildReasonVulnerabilityScannerNotConfigured indicates that the Build or its strategy has a
+	// vulnerability policy, but no container image with a vulnerability database is configured
+	BuildReasonVulnerabilityScannerNotConfigured = "VulnerabilityScannerNotConfigured"
+)
+
 // BuildStatus defines the observed state of Build
 type BuildStatus struct {
-	// The Register status of the Build
+	// ObservedGeneration is the generation of the Build spec that the
+	// conditions were computed for
 	// +optional
-	Registered corev1.ConditionStatus `json:"registered,omitempty"`
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
-	// The reason of the registered Build, either an error or succeed message
+	// Conditions holds the latest available observations of the Build, its
+	// Ready condition reports whether BuildRuns can use the Build
 	// +optional
-	Reason string `json:"reason,omitempty"`
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions corev1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastScheduleTime is the last time of the schedule that was handled, a BuildRun was created
+	// for it unless it was missed and skipped
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next time of the schedule at which a BuildRun will be created
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// ImageDigests are the digests that the images of the image trigger resolved to last
+	// +optional
+	ImageDigests []ImageDigest `json:"imageDigests,omitempty"`
+}
+
+// GetCondition returns the condition of the provided type, or nil if the Build does not have it
+func (bs *BuildStatus) GetCondition(t corev1alpha1.ConditionType) *corev1alpha1.Condition {
+	return getCondition(bs.Conditions, t)
+}
+
+// SetCondition adds the condition, or replaces the existing condition of the same type. The
+// last transition time is only changed when the status of the condition changes.
+func (bs *BuildStatus) SetCondition(condition *corev1alpha1.Condition) {
+	setCondition(&bs.Conditions, condition)
+}
+
+// MarkCondition sets the condition of the provided type with the provided status, reason and message
+func (bs *BuildStatus) MarkCondition(t corev1alpha1.ConditionType, status corev1.ConditionStatus, reason string, message string) {
+	bs.SetCondition(&corev1alpha1.Condition{
+		Type:               t,
+		Status:             status,
+		LastTransitionTime: corev1alpha1.VolatileTime{Inner: metav1.Now()},
+		Reason:             reason,
+		Message:            message,
+	})
 }
 
 // +genclient
@@ -135,8 +412,8 @@ type BuildStatus struct {
 // Build is the Schema representing a Build definition
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=builds,scope=Namespaced
-// +kubebuilder:printcolumn:name="Registered",type="string",JSONPath=".status.registered",description="The register status of the Build"
-// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.reason",description="The reason of the registered Build, either an error or succeed message"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="The Ready status of the Build"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",description="The Ready reason of the Build"
 // +kubebuilder:printcolumn:name="BuildStrategyKind",type="string",JSONPath=".spec.strategy.kind",description="The BuildStrategy type which is used for this Build"
 // +kubebuilder:printcolumn:name="BuildStrategyName",type="string",JSONPath=".spec.strategy.name",description="The BuildStrategy name which is used for this Build"
 // +kubebuilder:printcolumn:name="CreationTime",type="date",JSONPath=".metadata.creationTimestamp",description="The create time of this Build"
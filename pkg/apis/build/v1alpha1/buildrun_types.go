@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -15,6 +16,104 @@ var (
 
 	// LabelBuildRunGeneration is a label key for BuildRuns to define the generation
 	LabelBuildRunGeneration = "buildrun.build.dev/generation"
+
+	// LabelBuildRunPlatform is a label key for the TaskRuns of a BuildRun to define the platform
+	// that they build, like linux-arm64 for the platform linux/arm64
+	LabelBuildRunPlatform = "buildrun.build.dev/platform"
+
+	// AnnotationBuildRunTrigger is an annotation key for BuildRuns that a trigger of their Build
+	// created, it names the trigger, for example webhook
+	AnnotationBuildRunTrigger = "buildrun.build.dev/trigger"
+
+	// AnnotationBuildRunTriggerImages is an annotation key for BuildRuns that the image trigger
+	// of their Build created, it lists the changed images with their new digests
+	AnnotationBuildRunTriggerImages = "buildrun.build.dev/trigger-images"
+
+	// AnnotationBuildRunStrategyDigest is an annotation key for the TaskRuns of a BuildRun, it is
+	// the sha256 digest of the steps of the build strategy that the TaskRun runs
+	AnnotationBuildRunStrategyDigest = "buildrun.build.dev/strategy-digest"
+)
+
+// Reasons of the Succeeded condition of a BuildRun
+const (
+	// BuildRunReasonPending indicates that the TaskRun of the BuildRun is created, but did not yet start
+	BuildRunReasonPending = "Pending"
+
+	// BuildRunReasonRunning indicates that the TaskRun of the BuildRun is running
+	BuildRunReasonRunning = "Running"
+
+	// BuildRunReasonSucceeded indicates that the BuildRun completed successfully
+	BuildRunReasonSucceeded = "Succeeded"
+
+	// BuildRunReasonFailed indicates that the TaskRun of the BuildRun failed
+	BuildRunReasonFailed = "Failed"
+
+	// BuildRunReasonBuildNotFound indicates that the referenced Build does not exist
+	BuildRunReasonBuildNotFound = "BuildNotFound"
+
+	// BuildRunReasonBuildRegistrationFailed indicates that the referenced Build is not registered
+	BuildRunReasonBuildRegistrationFailed = "BuildRegistrationFailed"
+
+	// BuildRunReasonStrategyNotFound indicates that the build strategy of the Build does not exist
+	BuildRunReasonStrategyNotFound = "StrategyNotFound"
+
+	// BuildRunReasonUnknownStrategyKind indicates that the kind of the build strategy of the Build is not supported
+	BuildRunReasonUnknownStrategyKind = "UnknownStrategyKind"
+
+	// BuildRunReasonServiceAccountNotFound indicates that the service account for the BuildRun could not be retrieved
+	BuildRunReasonServiceAccountNotFound = "ServiceAccountNotFound"
+
+	// BuildRunReasonTaskRunGenerationFailed indicates that the TaskRun could not be generated from the Build and strategy
+	BuildRunReasonTaskRunGenerationFailed = "TaskRunGenerationFailed"
+
+	// BuildRunReasonTaskRunCreationFailed indicates that the generated TaskRun could not be created
+	BuildRunReasonTaskRunCreationFailed = "TaskRunCreationFailed"
+
+	// BuildRunReasonTaskRunNotFound indicates that the TaskRun of the BuildRun was deleted
+	BuildRunReasonTaskRunNotFound = "TaskRunNotFound"
+
+	// BuildRunReasonTimeout indicates that the BuildRun exceeded its timeout
+	BuildRunReasonTimeout = "Timeout"
+
+	// BuildRunReasonCancelled indicates that the BuildRun was cancelled
+	BuildRunReasonCancelled = "Cancelled"
+
+	// BuildRunReasonRetrying indicates that the TaskRun of the BuildRun failed, and that the
+	// BuildRun waits for the backoff before it creates a new TaskRun
+	BuildRunReasonRetrying = "Retrying"
+
+	// BuildRunReasonConcurrencyForbidden indicates that the BuildRun did not start, because another BuildRun
+	// of the Build is running and the concurrency policy of the Build is Forbid
+	BuildRunReasonConcurrencyForbidden = "ConcurrencyForbidden"
+
+	// BuildRunReasonReplaced indicates that the BuildRun did not start, because a newer BuildRun of the
+	// Build replaces it
+	BuildRunReasonReplaced = "Replaced"
+
+	// BuildRunReasonTaggingFailed indicates that the image was pushed, but that its further tags
+	// could not be added
+	BuildRunReasonTaggingFailed = "TaggingFailed"
+
+	// BuildRunReasonImageIndexFailed indicates that the images of all platforms were pushed, but
+	// that their image index could not be pushed
+	BuildRunReasonImageIndexFailed = "ImageIndexFailed"
+
+	// BuildRunReasonVulnerabilityPolicyViolation indicates that the pushed image has
+	// vulnerabilities of the severity of the vulnerability policy or a higher one
+	BuildRunReasonVulnerabilityPolicyViolation = "VulnerabilityPolicyViolation"
+
+	// BuildRunReasonProvenanceFailed indicates that the image was pushed, but that the
+	// attestation of its provenance could not be pushed
+	BuildRunReasonProvenanceFailed = "ProvenanceFailed"
+)
+
+// BuildRunState is the state that the user requests for a BuildRun
+// +kubebuilder:validation:Enum=Cancelled
+type BuildRunState string
+
+const (
+	// BuildRunStateCancelled requests to stop the BuildRun, its TaskRun is cancelled
+	BuildRunStateCancelled BuildRunState = "Cancelled"
 )
 
 // BuildRunSpec defines the desired state of BuildRun
@@ -38,23 +137,72 @@ type BuildRunSpec struct {
 	// image would be pushed to. It will overwrite the output image in build spec
 	// +optional
 	Output *Image `json:"output,omitempty"`
+
+	// Revision refers to the git revision to build, it can be a commit,
+	// a branch or a tag. It will overwrite the source revision in build spec
+	// +optional
+	Revision *string `json:"revision,omitempty"`
+
+	// BuilderImage refers to the image containing the build tools.
+	// It will overwrite the builder image in build spec
+	// +optional
+	BuilderImage *Image `json:"builder,omitempty"`
+
+	// Parameters contains name-value pairs for the parameters declared by the
+	// build strategy. They will overwrite the parameters with the same name in build spec
+	// +optional
+	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// State is used to cancel the BuildRun with the value Cancelled. A running TaskRun is
+	// stopped, and the BuildRun completes with the reason Cancelled.
+	// +optional
+	State BuildRunState `json:"state,omitempty"`
+
+	// Retries defines how often a failed TaskRun is retried. It will overwrite the
+	// retries in build spec
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
+
+	// Priority orders the BuildRuns of a namespace that a ClusterBuildRunQuota queues, a
+	// BuildRun with a higher priority is admitted first. The default is zero.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+}
+
+// IsCancelled returns true if the BuildRun was requested to be cancelled
+func (brs *BuildRunSpec) IsCancelled() bool {
+	return brs.State == BuildRunStateCancelled
 }
 
 // BuildRunStatus defines the observed state of BuildRun
 type BuildRunStatus struct {
 
-	// The Succeeded status of the TaskRun
-	// +optional
-	Succeeded corev1.ConditionStatus `json:"succeeded,omitempty"`
-
-	// The Succeeded reason of the TaskRun
+	// Conditions holds the latest available observations of the BuildRun, its
+	// Succeeded condition reports whether the BuildRun completed successfully
 	// +optional
-	Reason string `json:"reason,omitempty"`
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions corev1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
 	// PodName is the name of the pod responsible for executing this task's steps.
 	// +optional
 	LatestTaskRunRef *string `json:"latestTaskRunRef,omitempty"`
 
+	// TaskRunRefs are the names of all TaskRuns of the BuildRun, the first attempt
+	// first, the latest one is also in LatestTaskRunRef
+	// +optional
+	TaskRunRefs []string `json:"taskRunRefs,omitempty"`
+
+	// Attempts is the number of TaskRuns that the BuildRun created, it is larger
+	// than one if failed TaskRuns were retried
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// QueuePosition is the position of the BuildRun in the queue of a ClusterBuildRunQuota,
+	// it is only set while the BuildRun waits to be admitted
+	// +optional
+	QueuePosition *int32 `json:"queuePosition,omitempty"`
+
 	// StartTime is the time the build is actually started.
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
@@ -63,9 +211,151 @@ type BuildRunStatus struct {
 	// +optional
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 
-	// BuildSpec is the Build Spec of this BuildRun.
+	// BuildSpec is the Build Spec of this BuildRun, merged with the
+	// overrides defined in the BuildRun spec.
 	// +optional
 	BuildSpec *BuildSpec `json:"buildSpec,omitempty"`
+
+	// Output holds the information about the image that the BuildRun pushed,
+	// as far as the build strategy reported it
+	// +optional
+	Output *BuildRunOutput `json:"output,omitempty"`
+
+	// Sources holds the information about the sources that the BuildRun built,
+	// for example the git commit that was checked out
+	// +optional
+	Sources []SourceResult `json:"sources,omitempty"`
+
+	// CommitStatusSha is the commit that the commit statuses of the BuildRun are reported for,
+	// it is resolved once for the first report, so that all reports go to the same commit
+	// +optional
+	CommitStatusSha string `json:"commitStatusSha,omitempty"`
+
+	// Platforms holds the TaskRuns and the images of the platforms of a Build
+	// with platforms, the image index of all platforms is in Output
+	// +optional
+	Platforms []PlatformResult `json:"platforms,omitempty"`
+
+	// Vulnerabilities counts the vulnerabilities of the pushed image by severity, if the
+	// Build or its strategy has a vulnerability policy
+	// +optional
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+}
+
+// PlatformResult holds the information about the image that a BuildRun built for a platform
+type PlatformResult struct {
+	// Platform is the platform, for example linux/arm64
+	Platform string `json:"platform"`
+
+	// TaskRunRef is the name of the TaskRun that builds the image of the platform
+	// +optional
+	TaskRunRef string `json:"taskRunRef,omitempty"`
+
+	// Image is the URL that the image of the platform was pushed to
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Digest is the digest of the image of the platform
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Provenance is the reference of the provenance attestation of the image of the platform,
+	// if the Build pushes one
+	// +optional
+	Provenance string `json:"provenance,omitempty"`
+}
+
+// BuildRunOutput holds the information about the image that a BuildRun pushed
+type BuildRunOutput struct {
+	// Image is the URL of the image that was pushed
+	Image string `json:"image,omitempty"`
+
+	// Digest is the digest of the image that was pushed, for example sha256:...,
+	// for a Build with platforms it is the digest of the image index
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Size is the compressed size of the image in bytes
+	// +optional
+	Size int64 `json:"size,omitempty"`
+
+	// Images are the references of the image under all of its tags, the image first and
+	// then its further tags
+	// +optional
+	Images []string `json:"images,omitempty"`
+
+	// Signature is the reference of the signature of the image, if the Build signs its output
+	// +optional
+	Signature string `json:"signature,omitempty"`
+
+	// SBOM is the reference of the SBOM that is attached to the image, if the Build generates one
+	// +optional
+	SBOM string `json:"sbom,omitempty"`
+
+	// Provenance is the reference of the provenance attestation of the image, if the Build
+	// pushes one
+	// +optional
+	Provenance string `json:"provenance,omitempty"`
+}
+
+// SourceResult holds the information about a source that a BuildRun built
+type SourceResult struct {
+	// Name is the name of the source
+	Name string `json:"name"`
+
+	// Git holds the details of the git commit that was built
+	// +optional
+	Git *GitSourceResult `json:"git,omitempty"`
+}
+
+// GitSourceResult holds the details of the git commit that a BuildRun built
+type GitSourceResult struct {
+	// CommitSha is the SHA of the commit that was checked out
+	// +optional
+	CommitSha string `json:"commitSha,omitempty"`
+
+	// CommitAuthor is the author of the commit that was checked out
+	// +optional
+	CommitAuthor string `json:"commitAuthor,omitempty"`
+
+	// CommitTimestamp is the time when the commit was created
+	// +optional
+	CommitTimestamp *metav1.Time `json:"commitTimestamp,omitempty"`
+
+	// Branch is the branch or tag that was requested as revision, it is
+	// empty if a commit was requested
+	// +optional
+	Branch string `json:"branch,omitempty"`
+}
+
+// GetCondition returns the condition of the provided type, or nil if the BuildRun does not have it
+func (brs *BuildRunStatus) GetCondition(t corev1alpha1.ConditionType) *corev1alpha1.Condition {
+	return getCondition(brs.Conditions, t)
+}
+
+// IsFailed returns true if the Succeeded condition of the BuildRun is False
+func (brs *BuildRunStatus) IsFailed() bool {
+	return brs.GetCondition(corev1alpha1.ConditionSucceeded).IsFalse()
+}
+
+// SetCondition adds the condition, or replaces the existing condition of the same type. The
+// last transition time is only changed when the status of the condition changes.
+func (brs *BuildRunStatus) SetCondition(condition *corev1alpha1.Condition) {
+	setCondition(&brs.Conditions, condition)
+}
+
+// SetSucceededCondition sets the Succeeded condition of the BuildRun with the provided status, reason and message.
+// The severity is always Error, which is the empty value, because the Succeeded condition decides whether the
+// BuildRun failed.
+func (brs *BuildRunStatus) SetSucceededCondition(status corev1.ConditionStatus, reason string, message string) {
+	brs.SetCondition(&corev1alpha1.Condition{
+		Type:               corev1alpha1.ConditionSucceeded,
+		Status:             status,
+		Severity:           corev1alpha1.ConditionSeverityError,
+		LastTransitionTime: corev1alpha1.VolatileTime{Inner: metav1.Now()},
+		Reason:             reason,
+		Message:            message,
+	})
 }
 
 // BuildRef can be used to refer to a specific instance of a Build.
@@ -93,8 +383,8 @@ type ServiceAccount struct {
 // BuildRun is the Schema representing an instance of build execution
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=buildruns,scope=Namespaced,shortName=br;brs
-// +kubebuilder:printcolumn:name="Succeeded",type="string",JSONPath=".status.succeeded",description="The Succeeded status of the TaskRun"
-// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.reason",description="The Succeeded reason of the TaskRun"
+// +kubebuilder:printcolumn:name="Succeeded",type="string",JSONPath=".status.conditions[?(@.type==\"Succeeded\")].status",description="The Succeeded status of the BuildRun"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Succeeded\")].reason",description="The Succeeded reason of the BuildRun"
 // +kubebuilder:printcolumn:name="StartTime",type="date",JSONPath=".status.startTime",description="The start time of this BuildRun"
 // +kubebuilder:printcolumn:name="CompletionTime",type="date",JSONPath=".status.completionTime",description="The completion time of this BuildRun"
 type BuildRun struct {
@@ -12,6 +12,16 @@ import (
 // BuildStrategySpec defines the desired state of BuildStrategy
 type BuildStrategySpec struct {
 	BuildSteps []BuildStep `json:"buildSteps,omitempty"`
+
+	// Parameters defines the parameters that a Build can set
+	// for this strategy
+	// +optional
+	Parameters []ParameterDefinition `json:"parameters,omitempty"`
+
+	// VulnerabilityPolicy scans the pushed output image of the Builds of this strategy for
+	// vulnerabilities, a Build can override it
+	// +optional
+	VulnerabilityPolicy *VulnerabilityPolicy `json:"vulnerabilityPolicy,omitempty"`
 }
 
 // BuildStep defines a partial step that needs to run in container for
@@ -22,6 +32,13 @@ type BuildStep struct {
 
 // BuildStrategyStatus defines the observed state of BuildStrategy
 type BuildStrategyStatus struct {
+	// The Register status of the BuildStrategy
+	// +optional
+	Registered corev1.ConditionStatus `json:"registered,omitempty"`
+
+	// The reason of the registered BuildStrategy, either an error or succeed message
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // +genclient
@@ -30,6 +47,9 @@ type BuildStrategyStatus struct {
 // BuildStrategy is the Schema representing a strategy in the namespace scope to build images from source code.
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=buildstrategies,scope=Namespaced,shortName=bs;bss
+// +kubebuilder:printcolumn:name="Registered",type="string",JSONPath=".status.registered",description="The register status of the BuildStrategy"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.reason",description="The reason of the registered BuildStrategy, either an error or succeed message"
+// +kubebuilder:printcolumn:name="CreationTime",type="date",JSONPath=".metadata.creationTimestamp",description="The create time of this BuildStrategy"
 type BuildStrategy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -51,6 +71,29 @@ func init() {
 	SchemeBuilder.Register(&BuildStrategy{}, &BuildStrategyList{})
 }
 
+// BuilderStrategy defines the common elements of namespaced and cluster scoped build strategies
+type BuilderStrategy interface {
+	GetName() string
+	GetBuildSteps() []BuildStep
+	GetParameters() []ParameterDefinition
+	GetVulnerabilityPolicy() *VulnerabilityPolicy
+}
+
+// GetBuildSteps returns the build steps of the strategy
+func (s *BuildStrategy) GetBuildSteps() []BuildStep {
+	return s.Spec.BuildSteps
+}
+
+// GetParameters returns the parameters declared by the strategy
+func (s *BuildStrategy) GetParameters() []ParameterDefinition {
+	return s.Spec.Parameters
+}
+
+// GetVulnerabilityPolicy returns the vulnerability policy of the strategy
+func (s *BuildStrategy) GetVulnerabilityPolicy() *VulnerabilityPolicy {
+	return s.Spec.VulnerabilityPolicy
+}
+
 // StrategyRef can be used to refer to a specific instance of a buildstrategy.
 // Copied from CrossVersionObjectReference: https://github.com/kubernetes/kubernetes/blob/169df7434155cbbc22f1532cba8e0a9588e29ad8/pkg/apis/autoscaling/types.go#L64
 type StrategyRef struct {
@@ -0,0 +1,55 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterBuildRunQuotaSpec defines how many BuildRuns run at the same time
+type ClusterBuildRunQuotaSpec struct {
+	// MaxRunningPerNamespace is the maximum number of BuildRuns with a TaskRun that did not
+	// complete in a namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRunningPerNamespace *int32 `json:"maxRunningPerNamespace,omitempty"`
+
+	// MaxRunning is the maximum number of BuildRuns with a TaskRun that did not complete in
+	// the cluster.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRunning *int32 `json:"maxRunning,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterBuildRunQuota is the Schema limiting the BuildRuns that run at the same time. BuildRuns
+// beyond the limits are queued, and admitted fairly across namespaces.
+// +kubebuilder:resource:path=clusterbuildrunquotas,scope=Cluster,shortName=cbrq;cbrqs
+// +kubebuilder:printcolumn:name="MaxRunningPerNamespace",type="integer",JSONPath=".spec.maxRunningPerNamespace",description="The maximum number of running BuildRuns in a namespace"
+// +kubebuilder:printcolumn:name="MaxRunning",type="integer",JSONPath=".spec.maxRunning",description="The maximum number of running BuildRuns in the cluster"
+// +kubebuilder:printcolumn:name="CreationTime",type="date",JSONPath=".metadata.creationTimestamp",description="The create time of this ClusterBuildRunQuota"
+type ClusterBuildRunQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterBuildRunQuotaSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterBuildRunQuotaList contains a list of ClusterBuildRunQuota
+type ClusterBuildRunQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterBuildRunQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterBuildRunQuota{}, &ClusterBuildRunQuotaList{})
+}
@@ -15,6 +15,9 @@ import (
 // ClusterBuildStrategy is the Schema representing a strategy in the cluster scope to build images from source code.
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=clusterbuildstrategies,scope=Cluster,shortName=cbs;cbss
+// +kubebuilder:printcolumn:name="Registered",type="string",JSONPath=".status.registered",description="The register status of the ClusterBuildStrategy"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.reason",description="The reason of the registered ClusterBuildStrategy, either an error or succeed message"
+// +kubebuilder:printcolumn:name="CreationTime",type="date",JSONPath=".metadata.creationTimestamp",description="The create time of this ClusterBuildStrategy"
 type ClusterBuildStrategy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -35,3 +38,18 @@ type ClusterBuildStrategyList struct {
 func init() {
 	SchemeBuilder.Register(&ClusterBuildStrategy{}, &ClusterBuildStrategyList{})
 }
+
+// GetBuildSteps returns the build steps of the strategy
+func (s *ClusterBuildStrategy) GetBuildSteps() []BuildStep {
+	return s.Spec.BuildSteps
+}
+
+// GetParameters returns the parameters declared by the strategy
+func (s *ClusterBuildStrategy) GetParameters() []ParameterDefinition {
+	return s.Spec.Parameters
+}
+
+// GetVulnerabilityPolicy returns the vulnerability policy of the strategy
+func (s *ClusterBuildStrategy) GetVulnerabilityPolicy() *VulnerabilityPolicy {
+	return s.Spec.VulnerabilityPolicy
+}
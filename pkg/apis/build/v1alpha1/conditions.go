@@ -0,0 +1,36 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+)
+
+// getCondition returns the condition of the provided type, or nil if the conditions do not contain it
+func getCondition(conditions corev1alpha1.Conditions, t corev1alpha1.ConditionType) *corev1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == t {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// setCondition adds the condition, or replaces the existing condition of the same type. The
+// last transition time is only changed when the status of the condition changes.
+func setCondition(conditions *corev1alpha1.Conditions, condition *corev1alpha1.Condition) {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status && !existing.LastTransitionTime.Inner.IsZero() {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = *condition
+		return
+	}
+	*conditions = append(*conditions, *condition)
+}
@@ -22,10 +22,10 @@ type GitSource struct {
 	// +optional
 	ContextDir *string `json:"contextDir,omitempty"`
 
-	// HTTPProxy is optional.
+	// HTTPProxy is the proxy for HTTP connections when fetching the source. Optional.
 	HTTPProxy string `json:"httpProxy,omitempty"`
 
-	// HTTPSProxy is optional.
+	// HTTPSProxy is the proxy for HTTPS connections when fetching the source. Optional.
 	HTTPSProxy string `json:"httpsProxy,omitempty"`
 
 	// NoProxy can be used to specify domains for which no proxying should be performed. Optional.
@@ -34,6 +34,7 @@ type GitSource struct {
 	// SecretRef refers to the secret that contains credentials to access the git repo. Optional.
 	SecretRef *corev1.LocalObjectReference `json:"credentials,omitempty"`
 
-	// Flavor of the git provider like github, gitlab, bitbucket, generic, etc. Optional.
+	// Flavor of the git provider, one of github, gitlab, bitbucket or generic. For github,
+	// gitlab and bitbucket, the BuildRun reports its state as commit status. Optional.
 	Flavor string `json:"flavor,omitempty"`
 }
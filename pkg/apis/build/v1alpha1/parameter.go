@@ -4,9 +4,68 @@
 
 package v1alpha1
 
+// ParameterType indicates the type of a parameter
+type ParameterType string
+
+const (
+	// ParameterTypeString indicates that the parameter holds a single string value
+	ParameterTypeString ParameterType = "string"
+	// ParameterTypeArray indicates that the parameter holds a list of string values
+	ParameterTypeArray ParameterType = "array"
+)
+
 // Parameter defines the data structure that would be used for
 // expressing arbitrary key/value pairs for the execution of a build
 type Parameter struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name string `json:"name"`
+
+	// Value of a parameter of type string
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Values of a parameter of type array
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// ParameterDefinition defines a parameter that a build strategy accepts
+type ParameterDefinition struct {
+	// Name of the parameter, it is referenced in the build steps
+	// as $(build.parameters.<name>)
+	Name string `json:"name"`
+
+	// Description of the parameter
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type of the parameter, either string or array. The default is string.
+	// +optional
+	// +kubebuilder:validation:Enum=string;array
+	Type ParameterType `json:"type,omitempty"`
+
+	// Default value of a parameter of type string. A parameter
+	// without a default value must be set in the Build.
+	// +optional
+	Default *string `json:"default,omitempty"`
+
+	// Defaults are the default values of a parameter of type array. A
+	// parameter without default values must be set in the Build.
+	// +optional
+	Defaults *[]string `json:"defaults,omitempty"`
+}
+
+// GetType returns the type of the parameter, defaulting to string
+func (p *ParameterDefinition) GetType() ParameterType {
+	if p.Type == "" {
+		return ParameterTypeString
+	}
+	return p.Type
+}
+
+// IsRequired returns true if the parameter has no default value
+func (p *ParameterDefinition) IsRequired() bool {
+	if p.GetType() == ParameterTypeArray {
+		return p.Defaults == nil
+	}
+	return p.Default == nil
 }
@@ -0,0 +1,31 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// ScheduleCatchUpPolicy defines what happens with the times of a schedule that were missed, for
+// example while the controller was not running
+// +kubebuilder:validation:Enum=Skip;RunOnce
+type ScheduleCatchUpPolicy string
+
+const (
+	// ScheduleCatchUpSkip does not create BuildRuns for missed times, the next BuildRun is
+	// created at the next time of the schedule
+	ScheduleCatchUpSkip ScheduleCatchUpPolicy = "Skip"
+
+	// ScheduleCatchUpRunOnce creates a single BuildRun for all missed times
+	ScheduleCatchUpRunOnce ScheduleCatchUpPolicy = "RunOnce"
+)
+
+// Schedule defines the times at which BuildRuns of a Build are created
+type Schedule struct {
+	// Cron is a cron expression with the five fields minute, hour, day of month, month and
+	// day of week, like "0 2 * * *". The times are in UTC.
+	Cron string `json:"cron"`
+
+	// CatchUpPolicy defines what happens with times of the schedule that were missed, Skip
+	// is used by default.
+	// +optional
+	CatchUpPolicy ScheduleCatchUpPolicy `json:"catchUpPolicy,omitempty"`
+}
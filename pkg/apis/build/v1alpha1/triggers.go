@@ -0,0 +1,76 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebhookEventType is the type of a webhook event of a git provider
+// +kubebuilder:validation:Enum=Push;PullRequest
+type WebhookEventType string
+
+const (
+	// WebhookEventPush is the push of commits to a branch
+	WebhookEventPush WebhookEventType = "Push"
+
+	// WebhookEventPullRequest is the opening of a pull request, or the push of commits to it. For
+	// GitLab, this is a merge request.
+	WebhookEventPullRequest WebhookEventType = "PullRequest"
+)
+
+// WebhookSecretKey is the key in the secret of a webhook trigger that holds the webhook secret
+const WebhookSecretKey = "secret"
+
+// Triggers defines the events that create BuildRuns of a Build
+type Triggers struct {
+	// Webhook defines the webhook events of the git provider that create BuildRuns.
+	// +optional
+	Webhook *WebhookTrigger `json:"webhook,omitempty"`
+
+	// Image defines that changes of the builder image and of the runtime base image
+	// create BuildRuns.
+	// +optional
+	Image *ImageTrigger `json:"image,omitempty"`
+}
+
+// WebhookTrigger defines which push and pull request webhooks of the git provider of the
+// source create BuildRuns
+type WebhookTrigger struct {
+	// SecretRef refers to the secret that holds the webhook secret in its key "secret". It
+	// verifies the HMAC signature of GitHub webhooks and the token of GitLab webhooks.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// Events are the types of events that create BuildRuns. Push is used by default.
+	// +optional
+	Events []WebhookEventType `json:"events,omitempty"`
+
+	// Branches are the branches for which events create BuildRuns, the pushed branch for a
+	// push, and the target branch for a pull request. A branch can be a shell file name
+	// pattern like release-*. The revision of the source is used by default.
+	// +optional
+	Branches []string `json:"branches,omitempty"`
+}
+
+// ImageTrigger defines how often the builder image and the runtime base image of a Build are
+// resolved to their digests. A BuildRun is created when a digest changes, for example because
+// a tag was pushed with security fixes.
+type ImageTrigger struct {
+	// Interval is the time between the resolutions of the images, one hour by default. Intervals
+	// shorter than five minutes are raised to five minutes.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ImageDigest is the digest that an image reference of a Build resolved to
+type ImageDigest struct {
+	// Image is the image reference, like it is in the Build
+	Image string `json:"image"`
+
+	// Digest is the digest of the image, for example sha256:...
+	Digest string `json:"digest"`
+}
@@ -0,0 +1,52 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// VulnerabilitySeverity is the severity of a vulnerability of an image
+// +kubebuilder:validation:Enum=Low;Medium;High;Critical
+type VulnerabilitySeverity string
+
+const (
+	// VulnerabilitySeverityLow is the severity of vulnerabilities with a low impact
+	VulnerabilitySeverityLow VulnerabilitySeverity = "Low"
+
+	// VulnerabilitySeverityMedium is the severity of vulnerabilities with a medium impact
+	VulnerabilitySeverityMedium VulnerabilitySeverity = "Medium"
+
+	// VulnerabilitySeverityHigh is the severity of vulnerabilities with a high impact
+	VulnerabilitySeverityHigh VulnerabilitySeverity = "High"
+
+	// VulnerabilitySeverityCritical is the severity of vulnerabilities with a critical impact
+	VulnerabilitySeverityCritical VulnerabilitySeverity = "Critical"
+)
+
+// VulnerabilityPolicy defines the vulnerability scan of the pushed output image. A BuildRun
+// fails if the image has vulnerabilities of the severity of the policy or a higher one.
+type VulnerabilityPolicy struct {
+	// Severity is the lowest severity of vulnerabilities that fail the BuildRun.
+	Severity VulnerabilitySeverity `json:"severity"`
+
+	// IgnoreUnfixed ignores vulnerabilities for which no fixed version of the package exists.
+	// +optional
+	IgnoreUnfixed bool `json:"ignoreUnfixed,omitempty"`
+}
+
+// VulnerabilitySummary counts the vulnerabilities of an image by severity
+type VulnerabilitySummary struct {
+	// Critical is the number of vulnerabilities with a critical severity
+	Critical int32 `json:"critical"`
+
+	// High is the number of vulnerabilities with a high severity
+	High int32 `json:"high"`
+
+	// Medium is the number of vulnerabilities with a medium severity
+	Medium int32 `json:"medium"`
+
+	// Low is the number of vulnerabilities with a low severity
+	Low int32 `json:"low"`
+
+	// Unknown is the number of vulnerabilities without a severity
+	Unknown int32 `json:"unknown"`
+}
@@ -29,6 +29,7 @@ type BuildV1alpha1Interface interface {
 	BuildsGetter
 	BuildRunsGetter
 	BuildStrategiesGetter
+	ClusterBuildRunQuotasGetter
 	ClusterBuildStrategiesGetter
 }
 
@@ -49,6 +50,10 @@ func (c *BuildV1alpha1Client) BuildStrategies(namespace string) BuildStrategyInt
 	return newBuildStrategies(c, namespace)
 }
 
+func (c *BuildV1alpha1Client) ClusterBuildRunQuotas() ClusterBuildRunQuotaInterface {
+	return newClusterBuildRunQuotas(c)
+}
+
 func (c *BuildV1alpha1Client) ClusterBuildStrategies() ClusterBuildStrategyInterface {
 	return newClusterBuildStrategies(c)
 }
@@ -40,6 +40,10 @@ func (c *FakeBuildV1alpha1) BuildStrategies(namespace string) v1alpha1.BuildStra
 	return &FakeBuildStrategies{c, namespace}
 }
 
+func (c *FakeBuildV1alpha1) ClusterBuildRunQuotas() v1alpha1.ClusterBuildRunQuotaInterface {
+	return &FakeClusterBuildRunQuotas{c}
+}
+
 func (c *FakeBuildV1alpha1) ClusterBuildStrategies() v1alpha1.ClusterBuildStrategyInterface {
 	return &FakeClusterBuildStrategies{c}
 }
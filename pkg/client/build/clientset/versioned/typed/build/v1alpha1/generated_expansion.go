@@ -24,4 +24,6 @@ type BuildRunExpansion interface{}
 
 type BuildStrategyExpansion interface{}
 
+type ClusterBuildRunQuotaExpansion interface{}
+
 type ClusterBuildStrategyExpansion interface{}
@@ -0,0 +1,101 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commitstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// bitbucket reports commit statuses through the API of Bitbucket Cloud, or through the build
+// status API of Bitbucket Server
+type bitbucket struct {
+	client *apiClient
+
+	// resolveURL and reportURL are format strings for the URL that resolves a revision and the
+	// URL that posts the status of a commit
+	resolveURL string
+	reportURL  string
+
+	// webURL is the link of a status that does not define its own, Bitbucket requires a link
+	webURL string
+}
+
+// bitbucketStates maps the states to the states of the Bitbucket build status API
+var bitbucketStates = map[State]string{
+	StateRunning:   "INPROGRESS",
+	StateSucceeded: "SUCCESSFUL",
+	StateFailed:    "FAILED",
+}
+
+func newBitbucket(c *apiClient, repo *repository) (*bitbucket, error) {
+	c.authenticate = func(request *http.Request, credentials Credentials) {
+		if credentials.Password != "" {
+			request.SetBasicAuth(credentials.Username, credentials.Password)
+		}
+	}
+
+	b := &bitbucket{client: c, webURL: repo.webURL()}
+
+	if repo.host == "bitbucket.org" {
+		apiURL := "https://api.bitbucket.org/2.0/repositories/" + repo.path
+		b.resolveURL = apiURL + "/commit/%s"
+		b.reportURL = apiURL + "/commit/%s/statuses/build"
+		return b, nil
+	}
+
+	// Bitbucket Server clones repositories from <context>/scm/<project>/<repository>
+	segments := strings.Split(repo.path, "/")
+	if len(segments) < 3 || segments[len(segments)-3] != "scm" {
+		return nil, fmt.Errorf("unsupported Bitbucket Server repository path %s", repo.path)
+	}
+	serverURL := fmt.Sprintf("%s://%s", repo.scheme, repo.host)
+	if contextPath := strings.Join(segments[:len(segments)-3], "/"); contextPath != "" {
+		serverURL += "/" + contextPath
+	}
+	project, slug := segments[len(segments)-2], segments[len(segments)-1]
+
+	b.resolveURL = fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/commits/", serverURL, project, slug) + "%s"
+	b.reportURL = serverURL + "/rest/build-status/1.0/commits/%s"
+	b.webURL = fmt.Sprintf("%s/projects/%s/repos/%s", serverURL, project, slug)
+	return b, nil
+}
+
+// ResolveRevision returns the SHA of the commit that a branch, tag or commit points to
+func (b *bitbucket) ResolveRevision(ctx context.Context, revision string) (string, error) {
+	// Bitbucket Cloud returns the SHA as hash, Bitbucket Server as id
+	var commit struct {
+		Hash string `json:"hash"`
+		ID   string `json:"id"`
+	}
+
+	if err := b.client.do(ctx, http.MethodGet, fmt.Sprintf(b.resolveURL, escapeRevision(revision)), nil, &commit); err != nil {
+		return "", err
+	}
+	if commit.Hash != "" {
+		return commit.Hash, nil
+	}
+	return commit.ID, nil
+}
+
+// Report posts the status for the commit with the provided SHA
+func (b *bitbucket) Report(ctx context.Context, sha string, status Status) error {
+	targetURL := status.TargetURL
+	if targetURL == "" {
+		targetURL = b.webURL
+	}
+
+	body := map[string]string{
+		"state":       bitbucketStates[status.State],
+		"key":         status.Context,
+		"name":        status.Context,
+		"description": truncate(status.Description),
+		"url":         targetURL,
+	}
+
+	return b.client.do(ctx, http.MethodPost, fmt.Sprintf(b.reportURL, sha), body, nil)
+}
@@ -0,0 +1,241 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package commitstatus reports the state of a build as commit status to the git provider
+// that hosts the source repository, so that it shows up as check on the commit. The
+// provider is selected through the flavor of the git source, the generic flavor does not
+// support commit statuses.
+package commitstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Flavors of git providers
+const (
+	FlavorGitHub    = "github"
+	FlavorGitLab    = "gitlab"
+	FlavorBitbucket = "bitbucket"
+	FlavorGeneric   = "generic"
+)
+
+// State is the state of a build that is reported as commit status
+type State string
+
+const (
+	// StateRunning indicates that the build started
+	StateRunning State = "Running"
+
+	// StateSucceeded indicates that the build completed successfully
+	StateSucceeded State = "Succeeded"
+
+	// StateFailed indicates that the build failed
+	StateFailed State = "Failed"
+)
+
+// maxDescriptionLength is the maximum length of a description that all providers accept
+const maxDescriptionLength = 140
+
+// Status is a commit status
+type Status struct {
+	// State is the state of the build
+	State State
+
+	// Context identifies the status among the other statuses of the commit
+	Context string
+
+	// Description is a short human readable text about the build
+	Description string
+
+	// TargetURL is a link to details about the build, it is optional
+	TargetURL string
+}
+
+// Credentials are the credentials that are used to access the API of the git provider, the
+// password is used as access token for providers that authenticate with a token
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Reporter reports commit statuses for a repository of a git provider
+type Reporter interface {
+	// ResolveRevision returns the SHA of the commit that a branch, tag or commit points to
+	ResolveRevision(ctx context.Context, revision string) (string, error)
+
+	// Report posts the status for the commit with the provided SHA
+	Report(ctx context.Context, sha string, status Status) error
+}
+
+// NewReporter returns the Reporter for the repository with the provided URL at the git provider
+// of the provided flavor. It returns nil, if the flavor is empty or generic. Without an HTTP
+// client, a client with a timeout is used, so that a slow git provider cannot block the caller.
+func NewReporter(flavor string, repoURL string, credentials Credentials, httpClient *http.Client) (Reporter, error) {
+	flavor = strings.ToLower(flavor)
+	if flavor == "" || flavor == FlavorGeneric {
+		return nil, nil
+	}
+
+	repo, err := parseRepositoryURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &apiClient{httpClient: httpClient, credentials: credentials}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	switch flavor {
+	case FlavorGitHub:
+		return newGitHub(c, repo), nil
+	case FlavorGitLab:
+		return newGitLab(c, repo), nil
+	case FlavorBitbucket:
+		return newBitbucket(c, repo)
+	default:
+		return nil, fmt.Errorf("unsupported git provider flavor %s", flavor)
+	}
+}
+
+// IsCommitSha returns true if the revision is the full SHA of a commit, which does not need to be resolved
+func IsCommitSha(revision string) bool {
+	if len(revision) != 40 {
+		return false
+	}
+	for _, c := range revision {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// repository is a repository at a git provider
+type repository struct {
+	// scheme of the web and API URLs, which is https for repositories that are cloned through ssh
+	scheme string
+
+	// host of the git provider, including the port
+	host string
+
+	// path of the repository without surrounding slashes and .git suffix, for example owner/repo
+	path string
+}
+
+// webURL returns the URL of the repository in the web interface of the git provider
+func (r *repository) webURL() string {
+	return fmt.Sprintf("%s://%s/%s", r.scheme, r.host, r.path)
+}
+
+// parseRepositoryURL parses the http(s), ssh and scp-like URLs of a git repository
+func parseRepositoryURL(repoURL string) (*repository, error) {
+	repo := &repository{scheme: "https"}
+
+	if strings.Contains(repoURL, "://") {
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "http" || u.Scheme == "https" {
+			repo.scheme = u.Scheme
+			repo.host = u.Host
+		} else {
+			// the ssh port does not serve the API
+			repo.host = u.Hostname()
+		}
+		repo.path = u.Path
+	} else if i := strings.Index(repoURL, ":"); i > 0 {
+		// scp-like syntax, for example git@github.com:owner/repo.git
+		repo.host = repoURL[:i]
+		if at := strings.LastIndex(repo.host, "@"); at >= 0 {
+			repo.host = repo.host[at+1:]
+		}
+		repo.path = repoURL[i+1:]
+	}
+
+	repo.path = strings.TrimSuffix(strings.Trim(repo.path, "/"), ".git")
+	if repo.host == "" || repo.path == "" {
+		return nil, fmt.Errorf("unsupported repository URL %s", repoURL)
+	}
+
+	return repo, nil
+}
+
+// escapeRevision escapes a revision for the use in a URL path, keeping the slashes of branch names
+func escapeRevision(revision string) string {
+	segments := strings.Split(revision, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// truncate shortens a description to the maximum length that all providers accept
+func truncate(description string) string {
+	if len(description) <= maxDescriptionLength {
+		return description
+	}
+	return description[:maxDescriptionLength-3] + "..."
+}
+
+// apiClient sends JSON requests to the API of a git provider
+type apiClient struct {
+	httpClient  *http.Client
+	credentials Credentials
+
+	// authenticate adds the credentials to a request in the way that the provider expects
+	authenticate func(request *http.Request, credentials Credentials)
+}
+
+// do sends a request with the provided body as JSON and decodes the JSON response into result,
+// body and result can be nil
+func (c *apiClient) do(ctx context.Context, method string, url string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.authenticate != nil {
+		c.authenticate(request, c.credentials)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s %s: unexpected response status %s", method, url, response.Status)
+	}
+
+	if result == nil {
+		_, err = io.Copy(ioutil.Discard, response.Body)
+		return err
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
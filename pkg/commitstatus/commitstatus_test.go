@@ -0,0 +1,239 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commitstatus_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/commitstatus"
+)
+
+// request is a request that the git provider stand-in received
+type request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]string
+}
+
+var _ = Describe("Reporter", func() {
+	const sha = "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"
+
+	var (
+		server   *httptest.Server
+		requests []request
+		response string
+	)
+
+	credentials := commitstatus.Credentials{Username: "jane", Password: "secret-token"}
+
+	status := commitstatus.Status{
+		State:       commitstatus.StateSucceeded,
+		Context:     "shipwright/taxi",
+		Description: "BuildRun taxi-xk2d9 succeeded",
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		response = "{}"
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received := request{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header}
+			if r.Body != nil && r.ContentLength > 0 {
+				Expect(json.NewDecoder(r.Body).Decode(&received.Body)).To(Succeed())
+			}
+			requests = append(requests, received)
+
+			if r.Header.Get("Authorization") == "token invalid" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(response))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("creating a reporter", func() {
+		It("should not report for the generic and the empty flavor", func() {
+			for _, flavor := range []string{"", commitstatus.FlavorGeneric} {
+				reporter, err := commitstatus.NewReporter(flavor, server.URL+"/owner/repo", credentials, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reporter).To(BeNil())
+			}
+		})
+
+		It("should fail for an unknown flavor", func() {
+			_, err := commitstatus.NewReporter("gitea", server.URL+"/owner/repo", credentials, nil)
+			Expect(err).To(MatchError("unsupported git provider flavor gitea"))
+		})
+
+		It("should fail for a repository URL without a path", func() {
+			_, err := commitstatus.NewReporter(commitstatus.FlavorGitHub, server.URL, credentials, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept ssh and scp-like repository URLs", func() {
+			for _, repoURL := range []string{"git@github.com:owner/repo.git", "ssh://git@github.com:22/owner/repo.git"} {
+				reporter, err := commitstatus.NewReporter(commitstatus.FlavorGitHub, repoURL, credentials, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reporter).ToNot(BeNil())
+			}
+		})
+	})
+
+	Context("for GitHub", func() {
+		var reporter commitstatus.Reporter
+
+		BeforeEach(func() {
+			var err error
+			reporter, err = commitstatus.NewReporter("GitHub", server.URL+"/owner/repo.git", credentials, server.Client())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should post the status to the enterprise API with the token", func() {
+			Expect(reporter.Report(context.TODO(), sha, status)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPost))
+			Expect(requests[0].Path).To(Equal("/api/v3/repos/owner/repo/statuses/" + sha))
+			Expect(requests[0].Header.Get("Authorization")).To(Equal("token secret-token"))
+			Expect(requests[0].Body).To(Equal(map[string]string{
+				"state":       "success",
+				"context":     "shipwright/taxi",
+				"description": "BuildRun taxi-xk2d9 succeeded",
+			}))
+		})
+
+		It("should map the running and failed states", func() {
+			Expect(reporter.Report(context.TODO(), sha, commitstatus.Status{State: commitstatus.StateRunning})).To(Succeed())
+			Expect(reporter.Report(context.TODO(), sha, commitstatus.Status{State: commitstatus.StateFailed})).To(Succeed())
+
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[0].Body["state"]).To(Equal("pending"))
+			Expect(requests[1].Body["state"]).To(Equal("failure"))
+		})
+
+		It("should resolve a branch", func() {
+			response = `{"sha": "` + sha + `"}`
+
+			resolved, err := reporter.ResolveRevision(context.TODO(), "feature/login")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(sha))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodGet))
+			Expect(requests[0].Path).To(Equal("/api/v3/repos/owner/repo/commits/feature/login"))
+		})
+
+		It("should return an error for an unsuccessful response", func() {
+			reporter, err := commitstatus.NewReporter(commitstatus.FlavorGitHub, server.URL+"/owner/repo", commitstatus.Credentials{Password: "invalid"}, server.Client())
+			Expect(err).ToNot(HaveOccurred())
+
+			err = reporter.Report(context.TODO(), sha, status)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("403 Forbidden"))
+		})
+
+		It("should truncate long descriptions", func() {
+			long := status
+			for len(long.Description) < 200 {
+				long.Description += " and more"
+			}
+			Expect(reporter.Report(context.TODO(), sha, long)).To(Succeed())
+			Expect(requests[0].Body["description"]).To(HaveLen(140))
+		})
+	})
+
+	Context("for GitLab", func() {
+		var reporter commitstatus.Reporter
+
+		BeforeEach(func() {
+			var err error
+			reporter, err = commitstatus.NewReporter(commitstatus.FlavorGitLab, server.URL+"/group/subgroup/repo", credentials, server.Client())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should post the status for the encoded project path with the private token", func() {
+			Expect(reporter.Report(context.TODO(), sha, status)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPost))
+			Expect(requests[0].Path).To(Equal("/api/v4/projects/group%2Fsubgroup%2Frepo/statuses/" + sha))
+			Expect(requests[0].Header.Get("PRIVATE-TOKEN")).To(Equal("secret-token"))
+			Expect(requests[0].Body).To(Equal(map[string]string{
+				"state":       "success",
+				"name":        "shipwright/taxi",
+				"description": "BuildRun taxi-xk2d9 succeeded",
+			}))
+		})
+
+		It("should resolve a branch", func() {
+			response = `{"id": "` + sha + `"}`
+
+			resolved, err := reporter.ResolveRevision(context.TODO(), "feature/login")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(sha))
+			Expect(requests[0].Path).To(Equal("/api/v4/projects/group%2Fsubgroup%2Frepo/repository/commits/feature%2Flogin"))
+		})
+	})
+
+	Context("for Bitbucket Server", func() {
+		var reporter commitstatus.Reporter
+
+		BeforeEach(func() {
+			var err error
+			reporter, err = commitstatus.NewReporter(commitstatus.FlavorBitbucket, server.URL+"/scm/proj/repo.git", credentials, server.Client())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should post the build status with basic authentication", func() {
+			Expect(reporter.Report(context.TODO(), sha, status)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPost))
+			Expect(requests[0].Path).To(Equal("/rest/build-status/1.0/commits/" + sha))
+			Expect(requests[0].Header.Get("Authorization")).To(HavePrefix("Basic "))
+			Expect(requests[0].Body).To(Equal(map[string]string{
+				"state":       "SUCCESSFUL",
+				"key":         "shipwright/taxi",
+				"name":        "shipwright/taxi",
+				"description": "BuildRun taxi-xk2d9 succeeded",
+				"url":         server.URL + "/projects/proj/repos/repo",
+			}))
+		})
+
+		It("should resolve a branch", func() {
+			response = `{"id": "` + sha + `"}`
+
+			resolved, err := reporter.ResolveRevision(context.TODO(), "master")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(sha))
+			Expect(requests[0].Path).To(Equal("/rest/api/1.0/projects/proj/repos/repo/commits/master"))
+		})
+
+		It("should fail for a repository path without scm segment", func() {
+			_, err := commitstatus.NewReporter(commitstatus.FlavorBitbucket, server.URL+"/proj/repo", credentials, server.Client())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("checking revisions", func() {
+		It("should only consider the full SHA of a commit", func() {
+			Expect(commitstatus.IsCommitSha(sha)).To(BeTrue())
+			Expect(commitstatus.IsCommitSha("master")).To(BeFalse())
+			Expect(commitstatus.IsCommitSha(sha[:7])).To(BeFalse())
+		})
+	})
+})
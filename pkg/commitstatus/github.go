@@ -0,0 +1,69 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commitstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// gitHub reports commit statuses through the API of GitHub or GitHub Enterprise
+type gitHub struct {
+	client *apiClient
+	apiURL string
+	repo   *repository
+}
+
+// gitHubStates maps the states to the states of the GitHub statuses API
+var gitHubStates = map[State]string{
+	StateRunning:   "pending",
+	StateSucceeded: "success",
+	StateFailed:    "failure",
+}
+
+func newGitHub(c *apiClient, repo *repository) *gitHub {
+	c.authenticate = func(request *http.Request, credentials Credentials) {
+		if credentials.Password != "" {
+			request.Header.Set("Authorization", "token "+credentials.Password)
+		}
+	}
+
+	// GitHub Enterprise serves the API below the /api/v3 path of its host
+	apiURL := fmt.Sprintf("%s://%s/api/v3", repo.scheme, repo.host)
+	if repo.host == "github.com" {
+		apiURL = "https://api.github.com"
+	}
+
+	return &gitHub{client: c, apiURL: apiURL, repo: repo}
+}
+
+// ResolveRevision returns the SHA of the commit that a branch, tag or commit points to
+func (g *gitHub) ResolveRevision(ctx context.Context, revision string) (string, error) {
+	var commit struct {
+		Sha string `json:"sha"`
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/commits/%s", g.apiURL, g.repo.path, escapeRevision(revision))
+	if err := g.client.do(ctx, http.MethodGet, url, nil, &commit); err != nil {
+		return "", err
+	}
+	return commit.Sha, nil
+}
+
+// Report posts the status for the commit with the provided SHA
+func (g *gitHub) Report(ctx context.Context, sha string, status Status) error {
+	body := map[string]string{
+		"state":       gitHubStates[status.State],
+		"context":     status.Context,
+		"description": truncate(status.Description),
+	}
+	if status.TargetURL != "" {
+		body["target_url"] = status.TargetURL
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", g.apiURL, g.repo.path, sha)
+	return g.client.do(ctx, http.MethodPost, url, body, nil)
+}
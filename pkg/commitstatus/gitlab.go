@@ -0,0 +1,66 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package commitstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitLab reports commit statuses through the API of GitLab
+type gitLab struct {
+	client *apiClient
+	apiURL string
+}
+
+// gitLabStates maps the states to the states of the GitLab commit status API
+var gitLabStates = map[State]string{
+	StateRunning:   "running",
+	StateSucceeded: "success",
+	StateFailed:    "failed",
+}
+
+func newGitLab(c *apiClient, repo *repository) *gitLab {
+	c.authenticate = func(request *http.Request, credentials Credentials) {
+		if credentials.Password != "" {
+			request.Header.Set("PRIVATE-TOKEN", credentials.Password)
+		}
+	}
+
+	// the API identifies a project by its URL encoded path, which includes the groups
+	apiURL := fmt.Sprintf("%s://%s/api/v4/projects/%s", repo.scheme, repo.host, url.PathEscape(repo.path))
+
+	return &gitLab{client: c, apiURL: apiURL}
+}
+
+// ResolveRevision returns the SHA of the commit that a branch, tag or commit points to
+func (g *gitLab) ResolveRevision(ctx context.Context, revision string) (string, error) {
+	var commit struct {
+		ID string `json:"id"`
+	}
+
+	url := fmt.Sprintf("%s/repository/commits/%s", g.apiURL, url.PathEscape(revision))
+	if err := g.client.do(ctx, http.MethodGet, url, nil, &commit); err != nil {
+		return "", err
+	}
+	return commit.ID, nil
+}
+
+// Report posts the status for the commit with the provided SHA
+func (g *gitLab) Report(ctx context.Context, sha string, status Status) error {
+	body := map[string]string{
+		"state":       gitLabStates[status.State],
+		"name":        status.Context,
+		"description": truncate(status.Description),
+	}
+	if status.TargetURL != "" {
+		body["target_url"] = status.TargetURL
+	}
+
+	url := fmt.Sprintf("%s/statuses/%s", g.apiURL, sha)
+	return g.client.do(ctx, http.MethodPost, url, body, nil)
+}
@@ -24,6 +24,37 @@ const (
 	// KANIKO_CONTAINER_IMAGE="gcr.io/kaniko-project/executor:v0.24.0"
 	kanikoImageEnvVar = "KANIKO_CONTAINER_IMAGE"
 
+	gitDefaultImage = "docker.io/alpine/git:v2.26.2"
+	// gitImageEnvVar environment variable for the container image with the git CLI, for instance:
+	// GIT_CONTAINER_IMAGE="docker.io/alpine/git:v2.26.2"
+	gitImageEnvVar = "GIT_CONTAINER_IMAGE"
+
+	cosignDefaultImage = "gcr.io/projectsigstore/cosign:v1.5.1-dev"
+	// cosignImageEnvVar environment variable for the container image with the cosign CLI and a
+	// shell, for instance: COSIGN_CONTAINER_IMAGE="gcr.io/projectsigstore/cosign:v1.5.2-dev"
+	cosignImageEnvVar = "COSIGN_CONTAINER_IMAGE"
+
+	syftDefaultImage = "docker.io/anchore/syft:v0.59.0-debug"
+	// syftImageEnvVar environment variable for the container image with the syft CLI in /syft and
+	// a shell, for instance: SYFT_CONTAINER_IMAGE="docker.io/anchore/syft:v0.60.0-debug"
+	syftImageEnvVar = "SYFT_CONTAINER_IMAGE"
+
+	// trivyImageEnvVar environment variable for the container image with the trivy CLI and its
+	// vulnerability database, for instance: TRIVY_CONTAINER_IMAGE="registry.example.com/trivy-offline:0.20.0".
+	// There is no default image, because the published trivy images contain no database.
+	trivyImageEnvVar = "TRIVY_CONTAINER_IMAGE"
+
+	// environment variables for the default proxy settings of the source fetch, they are
+	// separate from HTTP_PROXY and friends, which would apply to the controller itself
+	sourceHTTPProxyEnvVar  = "SOURCE_HTTP_PROXY"
+	sourceHTTPSProxyEnvVar = "SOURCE_HTTPS_PROXY"
+	sourceNoProxyEnvVar    = "SOURCE_NO_PROXY"
+
+	webhookDefaultListenAddress = ":8080"
+	// webhookListenAddressEnvVar environment variable for the address on which the git webhooks
+	// are received, for instance: WEBHOOK_LISTEN_ADDRESS=":9090"
+	webhookListenAddressEnvVar = "WEBHOOK_LISTEN_ADDRESS"
+
 	// environment variable to override the buckets
 	metricBuildRunCompletionDurationBucketsEnvVar = "PROMETHEUS_BR_COMP_DUR_BUCKETS"
 	metricBuildRunEstablishDurationBucketsEnvVar  = "PROMETHEUS_BR_EST_DUR_BUCKETS"
@@ -45,9 +76,23 @@ var (
 type Config struct {
 	CtxTimeOut           time.Duration
 	KanikoContainerImage string
+	GitContainerImage    string
+	CosignContainerImage string
+	SyftContainerImage   string
+	TrivyContainerImage  string
+	SourceProxy          ProxyConfig
+	WebhookListenAddress string
 	Prometheus           PrometheusConfig
 }
 
+// ProxyConfig contains the default proxy settings for fetching the source
+// of a Build, a Build can override each of them in its source definition
+type ProxyConfig struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
 // PrometheusConfig contains the specific configuration for the
 type PrometheusConfig struct {
 	BuildRunCompletionDurationBuckets []float64
@@ -56,11 +101,15 @@ type PrometheusConfig struct {
 	HistogramEnabledLabels            []string
 }
 
-// NewDefaultConfig returns a new Config, with context timeout and default Kaniko image.
+// NewDefaultConfig returns a new Config, with context timeout and default Kaniko, git, cosign and syft images.
 func NewDefaultConfig() *Config {
 	return &Config{
 		CtxTimeOut:           contextTimeout,
 		KanikoContainerImage: kanikoDefaultImage,
+		GitContainerImage:    gitDefaultImage,
+		CosignContainerImage: cosignDefaultImage,
+		SyftContainerImage:   syftDefaultImage,
+		WebhookListenAddress: webhookDefaultListenAddress,
 		Prometheus: PrometheusConfig{
 			BuildRunCompletionDurationBuckets: metricBuildRunCompletionDurationBuckets,
 			BuildRunEstablishDurationBuckets:  metricBuildRunEstablishDurationBuckets,
@@ -83,6 +132,30 @@ func (c *Config) SetConfigFromEnv() error {
 		c.KanikoContainerImage = kanikoImage
 	}
 
+	if gitImage := os.Getenv(gitImageEnvVar); gitImage != "" {
+		c.GitContainerImage = gitImage
+	}
+
+	if cosignImage := os.Getenv(cosignImageEnvVar); cosignImage != "" {
+		c.CosignContainerImage = cosignImage
+	}
+
+	if syftImage := os.Getenv(syftImageEnvVar); syftImage != "" {
+		c.SyftContainerImage = syftImage
+	}
+
+	if trivyImage := os.Getenv(trivyImageEnvVar); trivyImage != "" {
+		c.TrivyContainerImage = trivyImage
+	}
+
+	c.SourceProxy.HTTPProxy = os.Getenv(sourceHTTPProxyEnvVar)
+	c.SourceProxy.HTTPSProxy = os.Getenv(sourceHTTPSProxyEnvVar)
+	c.SourceProxy.NoProxy = os.Getenv(sourceNoProxyEnvVar)
+
+	if webhookListenAddress := os.Getenv(webhookListenAddressEnvVar); webhookListenAddress != "" {
+		c.WebhookListenAddress = webhookListenAddress
+	}
+
 	if err := updateBucketsConfig(&c.Prometheus.BuildRunCompletionDurationBuckets, metricBuildRunCompletionDurationBucketsEnvVar); err != nil {
 		return err
 	}
@@ -35,6 +35,60 @@ var _ = Describe("Config", func() {
 			})
 		})
 
+		It("should allow for an override of the default git image using an environment variable", func() {
+			var overrides = map[string]string{"GIT_CONTAINER_IMAGE": "docker.io/alpine/git:v2.30.0"}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.GitContainerImage).To(Equal("docker.io/alpine/git:v2.30.0"))
+			})
+		})
+
+		It("should allow for an override of the default cosign image using an environment variable", func() {
+			var overrides = map[string]string{"COSIGN_CONTAINER_IMAGE": "gcr.io/projectsigstore/cosign:v1.3.0"}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.CosignContainerImage).To(Equal("gcr.io/projectsigstore/cosign:v1.3.0"))
+			})
+		})
+
+		It("should allow for an override of the default syft image using an environment variable", func() {
+			var overrides = map[string]string{"SYFT_CONTAINER_IMAGE": "docker.io/anchore/syft:v0.28.0"}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.SyftContainerImage).To(Equal("docker.io/anchore/syft:v0.28.0"))
+			})
+		})
+
+		It("should not have a default trivy image, because it needs a vulnerability database", func() {
+			Expect(NewDefaultConfig().TrivyContainerImage).To(BeEmpty())
+		})
+
+		It("should allow to set the trivy image using an environment variable", func() {
+			var overrides = map[string]string{"TRIVY_CONTAINER_IMAGE": "registry.example.com/trivy-offline:0.20.0"}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.TrivyContainerImage).To(Equal("registry.example.com/trivy-offline:0.20.0"))
+			})
+		})
+
+		It("should allow to set the default source proxy settings using environment variables", func() {
+			var overrides = map[string]string{
+				"SOURCE_HTTP_PROXY":  "http://proxy.example.com:3128",
+				"SOURCE_HTTPS_PROXY": "https://proxy.example.com:3129",
+				"SOURCE_NO_PROXY":    ".cluster.local,10.0.0.0/8",
+			}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.SourceProxy).To(Equal(ProxyConfig{
+					HTTPProxy:  "http://proxy.example.com:3128",
+					HTTPSProxy: "https://proxy.example.com:3129",
+					NoProxy:    ".cluster.local,10.0.0.0/8",
+				}))
+			})
+		})
+
+		It("should allow for an override of the webhook listen address using an environment variable", func() {
+			var overrides = map[string]string{"WEBHOOK_LISTEN_ADDRESS": ":9090"}
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.WebhookListenAddress).To(Equal(":9090"))
+			})
+		})
+
 		It("should allow for an override of the Prometheus buckets settings using an environment variable", func() {
 			var overrides = map[string]string{
 				"PROMETHEUS_BR_COMP_DUR_BUCKETS":   "1,2,3,4",
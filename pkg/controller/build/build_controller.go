@@ -12,10 +12,13 @@ import (
 
 	"github.com/pkg/errors"
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/controller/utils"
+	"github.com/shipwright-io/build/pkg/cron"
 	"github.com/shipwright-io/build/pkg/ctxlog"
 	buildmetrics "github.com/shipwright-io/build/pkg/metrics"
+	"github.com/shipwright-io/build/pkg/registry"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
@@ -30,8 +33,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// succeedStatus default status for the Build CRD
-const succeedStatus string = "Succeeded"
 const namespace string = "namespace"
 const name string = "name"
 
@@ -141,11 +142,37 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 		return reconcile.Result{}, r.finalizeBuildRun(ctx, b)
 	}
 
-	// Populate the status struct with default values
-	b.Status.Registered = corev1.ConditionFalse
-	b.Status.Reason = succeedStatus
+	// Run all validations, every validation reports its result in a condition of the
+	// Build, so that all problems of the Build are visible at once
+	b.Status.ObservedGeneration = b.Generation
+	r.markSecretsResolved(ctx, b)
+	strategy := r.markStrategyResolved(ctx, b)
+	markParametersValid(b, strategy)
+	markRuntimeValid(ctx, b)
+	markScheduleValid(b)
+	markPlatformsValid(b)
+	markTagsValid(b)
+	r.markVulnerabilityPolicyValid(b, strategy)
+	notReadyErr := markReady(b)
 
-	// Validate if the referenced secrets exist in the namespace
+	updateErr := r.client.Status().Update(ctx, b)
+	if notReadyErr != nil {
+		return reconcile.Result{}, fmt.Errorf("errors: %v %v", notReadyErr, updateErr)
+	}
+	if updateErr != nil {
+		return reconcile.Result{}, updateErr
+	}
+
+	// Increase Build count in metrics
+	buildmetrics.BuildCountInc(b.Spec.StrategyRef.Name)
+
+	ctxlog.Debug(ctx, "finishing reconciling Build", namespace, request.Namespace, name, request.Name)
+	return reconcile.Result{}, nil
+}
+
+// markSecretsResolved sets the SecretsResolved condition, depending on whether the secrets
+// that the Build references exist in its namespace
+func (r *ReconcileBuild) markSecretsResolved(ctx context.Context, b *build.Build) {
 	var secretNames []string
 	if b.Spec.Output.SecretRef != nil && b.Spec.Output.SecretRef.Name != "" {
 		secretNames = append(secretNames, b.Spec.Output.SecretRef.Name)
@@ -156,120 +183,242 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 	if b.Spec.BuilderImage != nil && b.Spec.BuilderImage.SecretRef != nil && b.Spec.BuilderImage.SecretRef.Name != "" {
 		secretNames = append(secretNames, b.Spec.BuilderImage.SecretRef.Name)
 	}
+	if b.Spec.Triggers != nil && b.Spec.Triggers.Webhook != nil && b.Spec.Triggers.Webhook.SecretRef.Name != "" {
+		secretNames = append(secretNames, b.Spec.Triggers.Webhook.SecretRef.Name)
+	}
+	if b.Spec.Output.Signing != nil && b.Spec.Output.Signing.SecretRef.Name != "" {
+		secretNames = append(secretNames, b.Spec.Output.Signing.SecretRef.Name)
+	}
 
 	if len(secretNames) > 0 {
 		if err := r.validateSecrets(ctx, secretNames, b.Namespace); err != nil {
-			b.Status.Reason = err.Error()
-			updateErr := r.client.Status().Update(ctx, b)
-			return reconcile.Result{}, fmt.Errorf("errors: %v %v", err, updateErr)
+			b.Status.MarkCondition(build.BuildConditionSecretsResolved, corev1.ConditionFalse, build.BuildReasonSecretNotFound, err.Error())
+			return
 		}
 	}
+	b.Status.MarkCondition(build.BuildConditionSecretsResolved, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
+
+// markStrategyResolved sets the StrategyResolved condition, depending on whether the build strategy
+// of the Build exists and only uses known placeholders. It returns the strategy if it exists.
+func (r *ReconcileBuild) markStrategyResolved(ctx context.Context, b *build.Build) build.BuilderStrategy {
+	if b.Spec.StrategyRef == nil {
+		b.Status.MarkCondition(build.BuildConditionStrategyResolved, corev1.ConditionFalse, build.BuildReasonStrategyNotFound, "the Build does not reference a build strategy")
+		return nil
+	}
 
-	// Validate if the build strategy is defined
-	if b.Spec.StrategyRef != nil {
-		if err := r.validateStrategyRef(ctx, b.Spec.StrategyRef, b.Namespace); err != nil {
-			b.Status.Reason = err.Error()
-			updateErr := r.client.Status().Update(ctx, b)
-			return reconcile.Result{}, fmt.Errorf("errors: %v %v", err, updateErr)
+	strategy, err := r.validateStrategyRef(ctx, b.Spec.StrategyRef, b.Namespace)
+	if err != nil {
+		reason := build.BuildReasonStrategyNotFound
+		if kind := b.Spec.StrategyRef.Kind; kind != nil && *kind != build.NamespacedBuildStrategyKind && *kind != build.ClusterBuildStrategyKind {
+			reason = build.BuildReasonUnknownStrategyKind
 		}
-		ctxlog.Info(ctx, "build strategy found", namespace, b.Namespace, name, b.Name, "strategy", b.Spec.StrategyRef.Name)
+		b.Status.MarkCondition(build.BuildConditionStrategyResolved, corev1.ConditionFalse, reason, err.Error())
+		return nil
+	}
+	ctxlog.Info(ctx, "build strategy found", namespace, b.Namespace, name, b.Name, "strategy", b.Spec.StrategyRef.Name)
+
+	if err := utils.ValidateStrategyPlaceholders(strategy); err != nil {
+		b.Status.MarkCondition(build.BuildConditionStrategyResolved, corev1.ConditionFalse, build.BuildReasonStrategyInvalid, err.Error())
+		return strategy
+	}
+
+	b.Status.MarkCondition(build.BuildConditionStrategyResolved, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+	return strategy
+}
+
+// markParametersValid sets the ParametersValid condition, depending on whether the parameters of the
+// Build match the parameters declared by the build strategy. Without a strategy the condition is Unknown.
+func markParametersValid(b *build.Build, strategy build.BuilderStrategy) {
+	if strategy == nil {
+		b.Status.MarkCondition(build.BuildConditionParametersValid, corev1.ConditionUnknown, build.BuildReasonStrategyNotResolved, "the parameters cannot be validated without the build strategy")
+		return
 	}
 
-	// validate if "spec.runtime" attributes are valid
-	if utils.IsRuntimeDefined(b) {
-		if err := r.validateRuntime(b.Spec.Runtime); err != nil {
-			ctxlog.Error(ctx, err, "failed validating runtime attributes", "Build", b.Name)
-			b.Status.Reason = err.Error()
-			updateErr := r.client.Status().Update(ctx, b)
-			return reconcile.Result{}, fmt.Errorf("errors: %v %v", err, updateErr)
+	if err := utils.ValidateParameters(strategy.GetParameters(), utils.GetBuildParameters(b)); err != nil {
+		b.Status.MarkCondition(build.BuildConditionParametersValid, corev1.ConditionFalse, build.BuildReasonParametersInvalid, err.Error())
+		return
+	}
+	b.Status.MarkCondition(build.BuildConditionParametersValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
+
+// markRuntimeValid sets the RuntimeValid condition, depending on whether the "spec.runtime"
+// attributes of the Build are valid
+func markRuntimeValid(ctx context.Context, b *build.Build) {
+	if utils.IsRuntimeDefined(b) && len(b.Spec.Runtime.Paths) == 0 {
+		err := fmt.Errorf("the property 'spec.runtime.paths' must not be empty")
+		ctxlog.Error(ctx, err, "failed validating runtime attributes", "Build", b.Name)
+		b.Status.MarkCondition(build.BuildConditionRuntimeValid, corev1.ConditionFalse, build.BuildReasonRuntimeInvalid, err.Error())
+		return
+	}
+	b.Status.MarkCondition(build.BuildConditionRuntimeValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
+
+// markScheduleValid sets the ScheduleValid condition, depending on whether the cron expression of
+// the "spec.schedule" of the Build can be parsed
+func markScheduleValid(b *build.Build) {
+	if b.Spec.Schedule != nil {
+		if _, err := cron.Parse(b.Spec.Schedule.Cron); err != nil {
+			b.Status.MarkCondition(build.BuildConditionScheduleValid, corev1.ConditionFalse, build.BuildReasonScheduleInvalid, err.Error())
+			return
 		}
 	}
+	b.Status.MarkCondition(build.BuildConditionScheduleValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
 
-	b.Status.Registered = corev1.ConditionTrue
-	err = r.client.Status().Update(ctx, b)
-	if err != nil {
-		return reconcile.Result{}, err
+// markPlatformsValid sets the PlatformsValid condition, depending on whether the "spec.platforms"
+// of the Build are of the form os/architecture[/variant], not duplicated, and not combined with
+// "spec.retries", "spec.output.signing" or "spec.output.sbom"
+func markPlatformsValid(b *build.Build) {
+	// the TaskRuns of platforms are not retried
+	if len(b.Spec.Platforms) > 0 && b.Spec.Retries != nil {
+		b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionFalse, build.BuildReasonPlatformsInvalid, "retries are not supported for a Build with platforms")
+		return
 	}
 
-	// Increase Build count in metrics
-	buildmetrics.BuildCountInc(b.Spec.StrategyRef.Name)
+	// the image index that the output image points to cannot be signed
+	if len(b.Spec.Platforms) > 0 && b.Spec.Output.Signing != nil {
+		b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionFalse, build.BuildReasonPlatformsInvalid, "signing is not supported for a Build with platforms")
+		return
+	}
 
-	ctxlog.Debug(ctx, "finishing reconciling Build", namespace, request.Namespace, name, request.Name)
-	return reconcile.Result{}, nil
+	// no SBOM can be attached to the image index that the output image points to
+	if len(b.Spec.Platforms) > 0 && b.Spec.Output.SBOM != nil {
+		b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionFalse, build.BuildReasonPlatformsInvalid, "SBOMs are not supported for a Build with platforms")
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, platform := range b.Spec.Platforms {
+		if _, err := registry.ParsePlatform(platform); err != nil {
+			b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionFalse, build.BuildReasonPlatformsInvalid, err.Error())
+			return
+		}
+		if seen[platform] {
+			b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionFalse, build.BuildReasonPlatformsInvalid, fmt.Sprintf("the platform %q is defined more than once", platform))
+			return
+		}
+		seen[platform] = true
+	}
+	b.Status.MarkCondition(build.BuildConditionPlatformsValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
 }
 
-func (r *ReconcileBuild) validateRuntime(runtime *build.Runtime) error {
-	if len(runtime.Paths) == 0 {
-		return fmt.Errorf("the property 'spec.runtime.paths' must not be empty")
+// markTagsValid sets the TagsValid condition, depending on whether the templates of the
+// "spec.output.tags" of the Build can be executed and result in valid tags
+func markTagsValid(b *build.Build) {
+	if err := utils.ValidateTagTemplates(b); err != nil {
+		b.Status.MarkCondition(build.BuildConditionTagsValid, corev1.ConditionFalse, build.BuildReasonTagsInvalid, err.Error())
+		return
 	}
+	b.Status.MarkCondition(build.BuildConditionTagsValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
+
+// markVulnerabilityPolicyValid sets the VulnerabilityPolicyValid condition, depending on whether a
+// container image with a vulnerability database is configured if the Build or its strategy has a
+// vulnerability policy
+func (r *ReconcileBuild) markVulnerabilityPolicyValid(b *build.Build, strategy build.BuilderStrategy) {
+	policy := b.Spec.VulnerabilityPolicy
+	if policy == nil && strategy != nil {
+		policy = strategy.GetVulnerabilityPolicy()
+	}
+
+	if policy != nil && r.config.TrivyContainerImage == "" {
+		b.Status.MarkCondition(build.BuildConditionVulnerabilityPolicyValid, corev1.ConditionFalse, build.BuildReasonVulnerabilityScannerNotConfigured, "the vulnerability policy cannot be enforced, because no container image with a vulnerability database is configured")
+		return
+	}
+	b.Status.MarkCondition(build.BuildConditionVulnerabilityPolicyValid, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
+}
+
+// markReady sets the Ready condition from the other conditions of the Build. If one of them is not
+// True, the Ready condition is False with the reason of the first one and the messages of all of them,
+// which are also returned as an error.
+func markReady(b *build.Build) error {
+	var reason string
+	var messages []string
+	for _, conditionType := range []corev1alpha1.ConditionType{
+		build.BuildConditionSecretsResolved,
+		build.BuildConditionStrategyResolved,
+		build.BuildConditionParametersValid,
+		build.BuildConditionRuntimeValid,
+		build.BuildConditionScheduleValid,
+		build.BuildConditionPlatformsValid,
+		build.BuildConditionTagsValid,
+		build.BuildConditionVulnerabilityPolicyValid,
+	} {
+		condition := b.Status.GetCondition(conditionType)
+		if condition.IsTrue() {
+			continue
+		}
+		if reason == "" {
+			reason = condition.Reason
+		}
+		if condition.IsFalse() {
+			messages = append(messages, condition.Message)
+		}
+	}
+
+	if reason != "" {
+		message := strings.Join(messages, "; ")
+		b.Status.MarkCondition(build.BuildConditionReady, corev1.ConditionFalse, reason, message)
+		return errors.New(message)
+	}
+	b.Status.MarkCondition(build.BuildConditionReady, corev1.ConditionTrue, build.BuildReasonSucceeded, "")
 	return nil
 }
 
-func (r *ReconcileBuild) validateStrategyRef(ctx context.Context, s *build.StrategyRef, ns string) error {
+// validateStrategyRef verifies that the referenced build strategy exists, and returns it
+func (r *ReconcileBuild) validateStrategyRef(ctx context.Context, s *build.StrategyRef, ns string) (build.BuilderStrategy, error) {
 	if s.Kind != nil {
 		switch *s.Kind {
 		case build.NamespacedBuildStrategyKind:
-			if err := r.validateBuildStrategy(ctx, s.Name, ns); err != nil {
-				return err
-			}
+			return r.validateBuildStrategy(ctx, s.Name, ns)
 		case build.ClusterBuildStrategyKind:
-			if err := r.validateClusterBuildStrategy(ctx, s.Name); err != nil {
-				return err
-			}
+			return r.validateClusterBuildStrategy(ctx, s.Name)
 		default:
-			return fmt.Errorf("unknown strategy %v", *s.Kind)
-		}
-	} else {
-		ctxlog.Info(ctx, "BuildStrategy kind is nil, use default NamespacedBuildStrategyKind")
-		if err := r.validateBuildStrategy(ctx, s.Name, ns); err != nil {
-			return err
+			return nil, fmt.Errorf("unknown strategy %v", *s.Kind)
 		}
 	}
-	return nil
+
+	ctxlog.Info(ctx, "BuildStrategy kind is nil, use default NamespacedBuildStrategyKind")
+	return r.validateBuildStrategy(ctx, s.Name, ns)
 }
 
-func (r *ReconcileBuild) validateBuildStrategy(ctx context.Context, n string, ns string) error {
+func (r *ReconcileBuild) validateBuildStrategy(ctx context.Context, n string, ns string) (build.BuilderStrategy, error) {
 	list := &build.BuildStrategyList{}
 
 	if err := r.client.List(ctx, list, &client.ListOptions{Namespace: ns}); err != nil {
-		return errors.Wrapf(err, "listing BuildStrategies in ns %s failed", ns)
+		return nil, errors.Wrapf(err, "listing BuildStrategies in ns %s failed", ns)
 	}
 
 	if len(list.Items) == 0 {
-		return errors.Errorf("none BuildStrategies found in namespace %s", ns)
+		return nil, errors.Errorf("none BuildStrategies found in namespace %s", ns)
 	}
 
-	if len(list.Items) > 0 {
-		for _, s := range list.Items {
-			if s.Name == n {
-				return nil
-			}
+	for i := range list.Items {
+		if list.Items[i].Name == n {
+			return &list.Items[i], nil
 		}
-		return fmt.Errorf("BuildStrategy %s does not exist in namespace %s", n, ns)
 	}
-	return nil
+	return nil, fmt.Errorf("BuildStrategy %s does not exist in namespace %s", n, ns)
 }
 
-func (r *ReconcileBuild) validateClusterBuildStrategy(ctx context.Context, n string) error {
+func (r *ReconcileBuild) validateClusterBuildStrategy(ctx context.Context, n string) (build.BuilderStrategy, error) {
 	list := &build.ClusterBuildStrategyList{}
 
 	if err := r.client.List(ctx, list); err != nil {
-		return errors.Wrapf(err, "listing ClusterBuildStrategies failed")
+		return nil, errors.Wrapf(err, "listing ClusterBuildStrategies failed")
 	}
 
 	if len(list.Items) == 0 {
-		return errors.Errorf("none ClusterBuildStrategies found")
+		return nil, errors.Errorf("none ClusterBuildStrategies found")
 	}
 
-	if len(list.Items) > 0 {
-		for _, s := range list.Items {
-			if s.Name == n {
-				return nil
-			}
+	for i := range list.Items {
+		if list.Items[i].Name == n {
+			return &list.Items[i], nil
 		}
-		return fmt.Errorf("clusterBuildStrategy %s does not exist", n)
 	}
-	return nil
+	return nil, fmt.Errorf("clusterBuildStrategy %s does not exist", n)
 }
 
 func (r *ReconcileBuild) validateSecrets(ctx context.Context, secretNames []string, ns string) error {
@@ -35,6 +35,7 @@ var _ = Describe("Reconcile Build", func() {
 		client                       *fakes.FakeClient
 		ctl                          test.Catalog
 		statusWriter                 *fakes.FakeStatusWriter
+		cfg                          *config.Config
 		registrySecret               string
 		buildName                    string
 		namespace, buildStrategyName string
@@ -65,6 +66,7 @@ var _ = Describe("Reconcile Build", func() {
 		statusWriter = &fakes.FakeStatusWriter{}
 		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
 		manager.GetClientReturns(client)
+		cfg = config.NewDefaultConfig()
 	})
 
 	JustBeforeEach(func() {
@@ -72,7 +74,7 @@ var _ = Describe("Reconcile Build", func() {
 		buildSample = ctl.BuildWithClusterBuildStrategy(buildName, namespace, buildStrategyName, registrySecret)
 		// Reconcile
 		testCtx := ctxlog.NewContext(context.TODO(), "fake-logger")
-		reconciler = buildController.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
+		reconciler = buildController.NewReconciler(testCtx, cfg, manager)
 	})
 
 	Describe("Reconcile", func() {
@@ -126,7 +128,7 @@ var _ = Describe("Reconcile Build", func() {
 					return nil
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -136,6 +138,64 @@ var _ = Describe("Reconcile Build", func() {
 			})
 		})
 
+		Context("when a webhook trigger secret is specified", func() {
+			It("fails when the secret does not exist", func() {
+				buildSample.Spec.Triggers = &build.Triggers{
+					Webhook: &build.WebhookTrigger{
+						SecretRef: corev1.LocalObjectReference{Name: "non-existing"},
+					},
+				}
+				buildSample.Spec.Output.SecretRef = nil
+
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.FakeSecretList()
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "secret non-existing does not exist")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when a signing secret is specified", func() {
+			It("fails when the secret does not exist", func() {
+				buildSample.Spec.Output.Signing = &build.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "non-existing"},
+				}
+				buildSample.Spec.Output.SecretRef = nil
+
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.FakeSecretList()
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "secret non-existing does not exist")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when builder image secret is specified", func() {
 			It("fails when the secret does not exist", func() {
 				buildSample.Spec.BuilderImage = &build.Image{
@@ -192,7 +252,7 @@ var _ = Describe("Reconcile Build", func() {
 					return nil
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -242,7 +302,7 @@ var _ = Describe("Reconcile Build", func() {
 					}
 					return nil
 				})
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -347,7 +407,7 @@ var _ = Describe("Reconcile Build", func() {
 					return nil
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -428,7 +488,7 @@ var _ = Describe("Reconcile Build", func() {
 					return nil
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -507,7 +567,7 @@ var _ = Describe("Reconcile Build", func() {
 					return nil
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionTrue, "Succeeded")
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
 				statusWriter.UpdateCalls(statusCall)
 
 				result, err := reconciler.Reconcile(request)
@@ -516,6 +576,394 @@ var _ = Describe("Reconcile Build", func() {
 				Expect(reconcile.Result{}).To(Equal(result))
 			})
 		})
+		Context("when the build sets parameters", func() {
+			var tag string
+
+			JustBeforeEach(func() {
+				tag = "latest"
+
+				// Fake some client LIST calls and ensure we populate all
+				// different resources we could get during reconciliation
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.Items[0].Spec.Parameters = []build.ParameterDefinition{
+							{Name: "storage-driver"},
+							{Name: "tag", Default: &tag},
+						}
+						list.Items[0].Spec.BuildSteps = []build.BuildStep{
+							{Container: corev1.Container{Name: "build", Image: "quay.io/buildah/stable:$(build.parameters.tag)", Args: []string{"--storage-driver=$(build.parameters.storage-driver)"}}},
+						}
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails when a parameter is not declared by the strategy", func() {
+				buildSample.Spec.Parameters = &[]build.Parameter{
+					{Name: "storage-driver", Value: "vfs"},
+					{Name: "unknown", Value: "foobar"},
+				}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "parameter \"unknown\" is not declared by the build strategy")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a required parameter is not set", func() {
+				buildSample.Spec.Parameters = &[]build.Parameter{
+					{Name: "tag", Value: "v1"},
+				}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "parameter \"storage-driver\" is required by the build strategy but not set")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("succeeds when all required parameters are set", func() {
+				buildSample.Spec.Parameters = &[]build.Parameter{
+					{Name: "storage-driver", Value: "vfs"},
+				}
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
+				statusWriter.UpdateCalls(statusCall)
+
+				result, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(reconcile.Result{}).To(Equal(result))
+			})
+		})
+		Context("when the strategy uses an unknown placeholder", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.Items[0].Spec.BuildSteps = []build.BuildStep{
+							{Container: corev1.Container{Name: "build", Image: "$(build.builder.imgae)"}},
+						}
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails to register the Build", func() {
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "the build strategy references unknown placeholder \"$(build.builder.imgae)\"")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the strategy declares a parameter that no step references", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.Items[0].Spec.Parameters = []build.ParameterDefinition{{Name: "storage-driver"}}
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails to register the Build", func() {
+				buildSample.Spec.Parameters = &[]build.Parameter{{Name: "storage-driver", Value: "vfs"}}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "the build strategy declares parameter \"storage-driver\", which no step references")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the build has a schedule", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails when the cron expression is invalid", func() {
+				buildSample.Spec.Schedule = &build.Schedule{Cron: "0 25 * * *"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "invalid hour \"25\"")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("succeeds when the cron expression is valid", func() {
+				buildSample.Spec.Schedule = &build.Schedule{Cron: "30 2 * * mon-fri"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the build has platforms", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails when a platform has no architecture", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "arm64"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "the platform \"arm64\" is not of the form os/architecture[/variant]")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a platform is duplicated", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/amd64"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "the platform \"linux/amd64\" is defined more than once")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the build also has retries", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/arm64"}
+				buildSample.Spec.Retries = &build.Retries{Max: 1}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "retries are not supported for a Build with platforms")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the build also signs its output", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/arm64"}
+				buildSample.Spec.Output.Signing = &build.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "signing-key"},
+				}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "signing is not supported for a Build with platforms")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the build also generates an SBOM", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/arm64"}
+				buildSample.Spec.Output.SBOM = &build.ImageSBOM{}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "SBOMs are not supported for a Build with platforms")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("succeeds when the platforms are valid", func() {
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/arm/v7"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the build has further tags", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails when a tag template references an unknown field", func() {
+				buildSample.Spec.Output.Tags = []string{"{{.Revison.SHA}}"}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					condition := object.(*build.Build).Status.GetCondition(build.BuildConditionTagsValid)
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(build.BuildReasonTagsInvalid))
+					Expect(condition.Message).To(ContainSubstring("{{.Revison.SHA}}"))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a tag template results in an invalid tag", func() {
+				buildSample.Spec.Output.Tags = []string{"release/{{.Revision.ShortSHA}}"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, "invalid tag \"release/0123456\"")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("succeeds when the tag templates are valid", func() {
+				buildSample.Spec.Output.Tags = []string{"{{.Revision.ShortSHA}}", "{{.BuildRun.Name}}-{{.Timestamp}}", "{{.Revision.Branch}}", "latest"}
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the build has a vulnerability policy", func() {
+			JustBeforeEach(func() {
+				buildSample.Spec.VulnerabilityPolicy = &build.VulnerabilityPolicy{Severity: build.VulnerabilitySeverityHigh}
+
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.SecretList(registrySecret)
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList(buildStrategyName)
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("fails when no image with a vulnerability database is configured", func() {
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					condition := object.(*build.Build).Status.GetCondition(build.BuildConditionVulnerabilityPolicyValid)
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(build.BuildReasonVulnerabilityScannerNotConfigured))
+					Expect(condition.Message).To(Equal("the vulnerability policy cannot be enforced, because no container image with a vulnerability database is configured"))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("succeeds when an image with a vulnerability database is configured", func() {
+				cfg.TrivyContainerImage = "registry.example.com/trivy-offline:0.20.0"
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, "")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+		Context("when the Build has several problems", func() {
+			JustBeforeEach(func() {
+				client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch object := object.(type) {
+					case *corev1.SecretList:
+						list := ctl.FakeSecretList()
+						list.DeepCopyInto(object)
+					case *build.ClusterBuildStrategyList:
+						list := ctl.ClusterBuildStrategyList("another-strategy")
+						list.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("reports all of them in the conditions of the Build", func() {
+				buildSample.Generation = 3
+				buildSample.Spec.Runtime = &build.Runtime{
+					Base: build.Image{ImageURL: "docker.io/library/alpine:latest"},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					b, ok := object.(*build.Build)
+					Expect(ok).To(BeTrue())
+					Expect(b.Status.ObservedGeneration).To(Equal(int64(3)))
+
+					Expect(b.Status.GetCondition(build.BuildConditionSecretsResolved).Status).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.GetCondition(build.BuildConditionSecretsResolved).Reason).To(Equal(build.BuildReasonSecretNotFound))
+					Expect(b.Status.GetCondition(build.BuildConditionStrategyResolved).Status).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.GetCondition(build.BuildConditionStrategyResolved).Reason).To(Equal(build.BuildReasonStrategyNotFound))
+					Expect(b.Status.GetCondition(build.BuildConditionParametersValid).Status).To(Equal(corev1.ConditionUnknown))
+					Expect(b.Status.GetCondition(build.BuildConditionRuntimeValid).Status).To(Equal(corev1.ConditionFalse))
+
+					ready := b.Status.GetCondition(build.BuildConditionReady)
+					Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+					Expect(ready.Reason).To(Equal(build.BuildReasonSecretNotFound))
+					Expect(ready.Message).To(ContainSubstring(fmt.Sprintf("secret %s does not exist", registrySecret)))
+					Expect(ready.Message).To(ContainSubstring(fmt.Sprintf("clusterBuildStrategy %s does not exist", buildStrategyName)))
+					Expect(ready.Message).To(ContainSubstring("the property 'spec.runtime.paths' must not be empty"))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
 		Context("when the annotation build-run-deletion is defined", func() {
 			var annotationFinalizer map[string]string
 
@@ -0,0 +1,234 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildimage
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	namespace string = "namespace"
+	name      string = "name"
+
+	// TriggerName is the value of the trigger annotation of the BuildRuns that the image trigger creates
+	TriggerName = "image"
+
+	// defaultInterval is the time between the resolutions of the images if the trigger does not define it
+	defaultInterval = time.Hour
+
+	// minInterval is the shortest time between the resolutions of the images, shorter intervals
+	// of a trigger are raised to it, so that the pull rate limits of registries are not hit
+	minInterval = 5 * time.Minute
+)
+
+// Add creates a new BuildImage Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContext(ctx, "buildimage-controller")
+	return add(mgr, NewReconciler(ctx, c, mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(ctx context.Context, c *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileBuildImage{
+		ctx:      ctx,
+		config:   c,
+		client:   mgr.GetClient(),
+		resolver: registry.NewResolver(nil),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("buildimage-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// The controller requeues every Build with an image trigger after its interval, so that
+	// only changes of the spec need to be watched
+	pred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	return c.Watch(&source.Kind{Type: &build.Build{}}, &handler.EnqueueRequestForObject{}, pred)
+}
+
+// blank assignment to verify that ReconcileBuildImage implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileBuildImage{}
+
+// ReconcileBuildImage creates BuildRuns when the images of a Build change
+type ReconcileBuildImage struct {
+	ctx      context.Context
+	config   *config.Config
+	client   client.Client
+	resolver *registry.Resolver
+}
+
+// Reconcile resolves the builder image and the runtime base image of a Build with an image
+// trigger to their digests, creates a BuildRun if a digest changed since the last resolution,
+// and requeues the Build after the interval of the trigger
+func (r *ReconcileBuildImage) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Debug(ctx, "start reconciling Build images", namespace, request.Namespace, name, request.Name)
+
+	b := &build.Build{}
+	if err := r.client.Get(ctx, request.NamespacedName, b); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if b.Spec.Triggers == nil || b.Spec.Triggers.Image == nil {
+		if len(b.Status.ImageDigests) == 0 {
+			return reconcile.Result{}, nil
+		}
+		b.Status.ImageDigests = nil
+		return reconcile.Result{}, r.client.Status().Update(ctx, b)
+	}
+
+	var digests []build.ImageDigest
+	var changed []string
+	for _, image := range getImages(b) {
+		previous := getDigest(b.Status.ImageDigests, image.ImageURL)
+
+		digest, err := r.resolve(ctx, b.Namespace, image)
+		if err != nil {
+			// the previous digest is kept, so that a registry that is temporarily not
+			// available does not look like a change of the image
+			ctxlog.Error(ctx, err, "failed to resolve the digest of the image", namespace, b.Namespace, name, b.Name, "image", image.ImageURL)
+			if previous != "" {
+				digests = append(digests, build.ImageDigest{Image: image.ImageURL, Digest: previous})
+			}
+			continue
+		}
+
+		digests = append(digests, build.ImageDigest{Image: image.ImageURL, Digest: digest})
+		// the first digest of an image is the baseline, it is no change
+		if previous != "" && previous != digest {
+			changed = append(changed, image.ImageURL+"@"+digest)
+		}
+	}
+
+	// the BuildRun is created before the digests are updated, a failed status update can
+	// therefore cause a second BuildRun, but a change is never lost
+	if len(changed) > 0 {
+		if err := r.createBuildRun(ctx, b, changed); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !reflect.DeepEqual(b.Status.ImageDigests, digests) {
+		b.Status.ImageDigests = digests
+		if err := r.client.Status().Update(ctx, b); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	interval := defaultInterval
+	if b.Spec.Triggers.Image.Interval != nil && b.Spec.Triggers.Image.Interval.Duration > 0 {
+		interval = b.Spec.Triggers.Image.Interval.Duration
+	}
+	if interval < minInterval {
+		interval = minInterval
+	}
+
+	ctxlog.Debug(ctx, "finishing reconciling Build images", namespace, request.Namespace, name, request.Name)
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+// resolve returns the digest of the image, with the credentials of its secret if it has one
+func (r *ReconcileBuildImage) resolve(ctx context.Context, ns string, image build.Image) (string, error) {
+	var credentials *registry.Credentials
+	if image.SecretRef != nil && image.SecretRef.Name != "" {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: image.SecretRef.Name}, secret); err != nil {
+			return "", err
+		}
+		if data, ok := secret.Data[corev1.DockerConfigJsonKey]; ok {
+			var err error
+			if credentials, err = registry.CredentialsFromDockerConfig(data, image.ImageURL); err != nil {
+				return "", err
+			}
+		}
+	}
+	return r.resolver.Resolve(ctx, image.ImageURL, credentials)
+}
+
+// createBuildRun creates a BuildRun of the Build, whose annotation records the changed images
+// with their new digests
+func (r *ReconcileBuildImage) createBuildRun(ctx context.Context, b *build.Build, changed []string) error {
+	buildRun := &build.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: b.Name + "-",
+			Namespace:    b.Namespace,
+			Annotations: map[string]string{
+				build.AnnotationBuildRunTrigger:       TriggerName,
+				build.AnnotationBuildRunTriggerImages: strings.Join(changed, ","),
+			},
+		},
+		Spec: build.BuildRunSpec{
+			BuildRef: &build.BuildRef{
+				Name: b.Name,
+			},
+		},
+	}
+
+	if err := r.client.Create(ctx, buildRun); err != nil {
+		return err
+	}
+	ctxlog.Info(ctx, "created a BuildRun for changed images", namespace, b.Namespace, name, b.Name, "buildrun", buildRun.Name, "images", strings.Join(changed, ","))
+	return nil
+}
+
+// getImages returns the builder image and the runtime base image of the Build, if it has them
+func getImages(b *build.Build) []build.Image {
+	var images []build.Image
+	if b.Spec.BuilderImage != nil && b.Spec.BuilderImage.ImageURL != "" {
+		images = append(images, *b.Spec.BuilderImage)
+	}
+	if b.Spec.Runtime != nil && b.Spec.Runtime.Base.ImageURL != "" {
+		images = append(images, b.Spec.Runtime.Base)
+	}
+	return images
+}
+
+// getDigest returns the digest of the image in the digests, or an empty string
+func getDigest(digests []build.ImageDigest, image string) string {
+	for _, digest := range digests {
+		if digest.Image == image {
+			return digest.Digest
+		}
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildimage_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/buildimage"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	oldDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	newDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+var _ = Describe("Reconcile BuildImage", func() {
+	var (
+		manager      *fakes.FakeManager
+		client       *fakes.FakeClient
+		statusWriter *fakes.FakeStatusWriter
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		buildSample  *build.Build
+		buildRuns    []*build.BuildRun
+		updated      *build.Build
+
+		// registry is a stand-in for a container registry, it serves the digests of the tags
+		registry     *httptest.Server
+		tags         map[string]string
+		builderImage string
+		baseImage    string
+	)
+
+	BeforeEach(func() {
+		buildRuns, updated = nil, nil
+
+		registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			digest, ok := tags[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		}))
+		host := strings.TrimPrefix(registry.URL, "http://")
+		builderImage = host + "/paketobuildpacks/builder:full"
+		baseImage = host + "/ubi8/ubi-minimal:latest"
+		tags = map[string]string{
+			"/v2/paketobuildpacks/builder/manifests/full": oldDigest,
+			"/v2/ubi8/ubi-minimal/manifests/latest":       oldDigest,
+		}
+
+		buildSample = &build.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "taxi", Namespace: "default"},
+			Spec: build.BuildSpec{
+				BuilderImage: &build.Image{ImageURL: builderImage},
+				Runtime: &build.Runtime{
+					Base: build.Image{ImageURL: baseImage},
+				},
+				Triggers: &build.Triggers{
+					Image: &build.ImageTrigger{Interval: &metav1.Duration{Duration: 10 * time.Minute}},
+				},
+			},
+		}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "taxi", Namespace: "default"}}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			switch object := object.(type) {
+			case *build.Build:
+				buildSample.DeepCopyInto(object)
+				return nil
+			case *corev1.Secret:
+				if nn.Name == "registry-secret" {
+					object.Data = map[string][]byte{
+						corev1.DockerConfigJsonKey: []byte(`{"auths": {"` + host + `": {"username": "user", "password": "pass"}}}`),
+					}
+					return nil
+				}
+			}
+			return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+			buildRuns = append(buildRuns, object.(*build.BuildRun))
+			return nil
+		})
+
+		statusWriter = &fakes.FakeStatusWriter{}
+		statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+			updated = object.(*build.Build)
+			return nil
+		})
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+
+		manager = &fakes.FakeManager{}
+		manager.GetClientReturns(client)
+	})
+
+	AfterEach(func() {
+		registry.Close()
+	})
+
+	JustBeforeEach(func() {
+		testCtx := ctxlog.NewContext(context.TODO(), "fake-logger")
+		reconciler = buildimage.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
+	})
+
+	It("raises an interval that is shorter than five minutes", func() {
+		buildSample.Spec.Triggers.Image.Interval = &metav1.Duration{Duration: time.Second}
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+	})
+
+	It("records the digests of the images without creating a BuildRun at the first resolution", func() {
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+
+		Expect(buildRuns).To(BeEmpty())
+		Expect(updated.Status.ImageDigests).To(Equal([]build.ImageDigest{
+			{Image: builderImage, Digest: oldDigest},
+			{Image: baseImage, Digest: oldDigest},
+		}))
+	})
+
+	It("creates a BuildRun when the digest of an image changes", func() {
+		buildSample.Status.ImageDigests = []build.ImageDigest{
+			{Image: builderImage, Digest: oldDigest},
+			{Image: baseImage, Digest: oldDigest},
+		}
+		tags["/v2/ubi8/ubi-minimal/manifests/latest"] = newDigest
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(buildRuns).To(HaveLen(1))
+		buildRun := buildRuns[0]
+		Expect(buildRun.GenerateName).To(Equal("taxi-"))
+		Expect(buildRun.Spec.BuildRef.Name).To(Equal("taxi"))
+		Expect(buildRun.Annotations).To(HaveKeyWithValue(build.AnnotationBuildRunTrigger, "image"))
+		Expect(buildRun.Annotations).To(HaveKeyWithValue(build.AnnotationBuildRunTriggerImages, baseImage+"@"+newDigest))
+
+		Expect(updated.Status.ImageDigests).To(ContainElement(build.ImageDigest{Image: baseImage, Digest: newDigest}))
+	})
+
+	It("does not update the Build if no digest changed", func() {
+		buildSample.Status.ImageDigests = []build.ImageDigest{
+			{Image: builderImage, Digest: oldDigest},
+			{Image: baseImage, Digest: oldDigest},
+		}
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buildRuns).To(BeEmpty())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("keeps the previous digest if an image cannot be resolved", func() {
+		buildSample.Status.ImageDigests = []build.ImageDigest{
+			{Image: builderImage, Digest: oldDigest},
+			{Image: baseImage, Digest: oldDigest},
+		}
+		delete(tags, "/v2/paketobuildpacks/builder/manifests/full")
+		tags["/v2/ubi8/ubi-minimal/manifests/latest"] = newDigest
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(buildRuns).To(HaveLen(1))
+		Expect(buildRuns[0].Annotations).To(HaveKeyWithValue(build.AnnotationBuildRunTriggerImages, baseImage+"@"+newDigest))
+		Expect(updated.Status.ImageDigests).To(ContainElement(build.ImageDigest{Image: builderImage, Digest: oldDigest}))
+	})
+
+	It("reads the credentials of an image from its secret", func() {
+		registry.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", newDigest)
+		})
+		buildSample.Spec.BuilderImage.SecretRef = &corev1.LocalObjectReference{Name: "registry-secret"}
+		buildSample.Spec.Runtime = nil
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Status.ImageDigests).To(Equal([]build.ImageDigest{
+			{Image: builderImage, Digest: newDigest},
+		}))
+	})
+
+	It("removes the digests when the trigger is removed", func() {
+		buildSample.Spec.Triggers = nil
+		buildSample.Status.ImageDigests = []build.ImageDigest{
+			{Image: builderImage, Digest: oldDigest},
+		}
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(updated.Status.ImageDigests).To(BeNil())
+	})
+})
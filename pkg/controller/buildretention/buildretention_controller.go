@@ -0,0 +1,198 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildretention
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	namespace string = "namespace"
+	name      string = "name"
+)
+
+// Add creates a new BuildRetention Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContext(ctx, "buildretention-controller")
+	return add(mgr, NewReconciler(ctx, c, mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(ctx context.Context, c *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileBuildRetention{
+		ctx:    ctx,
+		config: c,
+		client: mgr.GetClient(),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("buildretention-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	buildPred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	if err := c.Watch(&source.Kind{Type: &build.Build{}}, &handler.EnqueueRequestForObject{}, buildPred); err != nil {
+		return err
+	}
+
+	// A BuildRun that completes can push an older one beyond a limit, its Build is reconciled then
+	buildRunPred := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			o := e.Object.(*build.BuildRun)
+			return o.Status.CompletionTime != nil
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*build.BuildRun)
+			n := e.ObjectNew.(*build.BuildRun)
+			return o.Status.CompletionTime == nil && n.Status.CompletionTime != nil
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	return c.Watch(&source.Kind{Type: &build.BuildRun{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			buildName, ok := o.Meta.GetLabels()[build.LabelBuild]
+			if !ok || buildName == "" {
+				return nil
+			}
+			return []reconcile.Request{{
+				NamespacedName: types.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: buildName},
+			}}
+		}),
+	}, buildRunPred)
+}
+
+// blank assignment to verify that ReconcileBuildRetention implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileBuildRetention{}
+
+// ReconcileBuildRetention deletes the completed BuildRuns of a Build that its retention does not keep
+type ReconcileBuildRetention struct {
+	ctx    context.Context
+	config *config.Config
+	client client.Client
+}
+
+// Reconcile deletes the completed BuildRuns of a Build with a retention that are older than its
+// time to live or beyond its limits, and requeues the Build when the next BuildRun expires
+func (r *ReconcileBuildRetention) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Debug(ctx, "start reconciling Build retention", namespace, request.Namespace, name, request.Name)
+
+	b := &build.Build{}
+	if err := r.client.Get(ctx, request.NamespacedName, b); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	retention := b.Spec.Retention
+	if retention == nil {
+		return reconcile.Result{}, nil
+	}
+
+	buildRunList := &build.BuildRunList{}
+	if err := r.client.List(ctx, buildRunList, &client.ListOptions{
+		Namespace:     b.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{build.LabelBuild: b.Name}),
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	now := time.Now()
+	var succeeded, failed []*build.BuildRun
+	for i := range buildRunList.Items {
+		buildRun := &buildRunList.Items[i]
+		if buildRun.Status.CompletionTime == nil || buildRun.DeletionTimestamp != nil {
+			continue
+		}
+		if buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).IsTrue() {
+			succeeded = append(succeeded, buildRun)
+		} else {
+			failed = append(failed, buildRun)
+		}
+	}
+
+	var expired, kept []*build.BuildRun
+	for _, runs := range []struct {
+		buildRuns []*build.BuildRun
+		limit     *int32
+	}{{succeeded, retention.SucceededLimit}, {failed, retention.FailedLimit}} {
+		beyond, within := prune(runs.buildRuns, runs.limit)
+		expired = append(expired, beyond...)
+		kept = append(kept, within...)
+	}
+
+	var requeueAfter time.Duration
+	if retention.TTLAfterFinished != nil {
+		for _, buildRun := range kept {
+			remaining := buildRun.Status.CompletionTime.Add(retention.TTLAfterFinished.Duration).Sub(now)
+			if remaining <= 0 {
+				expired = append(expired, buildRun)
+				continue
+			}
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+		}
+	}
+
+	for _, buildRun := range expired {
+		if err := r.client.Delete(ctx, buildRun, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		ctxlog.Info(ctx, "deleted a BuildRun by the retention of its Build", namespace, b.Namespace, name, b.Name, "buildrun", buildRun.Name)
+	}
+
+	ctxlog.Debug(ctx, "finishing reconciling Build retention", namespace, request.Namespace, name, request.Name)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// prune splits the BuildRuns into the oldest ones beyond the limit and the latest ones that are kept
+func prune(buildRuns []*build.BuildRun, limit *int32) (expired []*build.BuildRun, kept []*build.BuildRun) {
+	if limit == nil || len(buildRuns) <= int(*limit) {
+		return nil, buildRuns
+	}
+
+	sort.Slice(buildRuns, func(i, j int) bool {
+		return buildRuns[i].Status.CompletionTime.After(buildRuns[j].Status.CompletionTime.Time)
+	})
+	return buildRuns[*limit:], buildRuns[:*limit]
+}
@@ -0,0 +1,198 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildretention_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/buildretention"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile BuildRetention", func() {
+	var (
+		manager     *fakes.FakeManager
+		client      *fakes.FakeClient
+		reconciler  reconcile.Reconciler
+		request     reconcile.Request
+		buildSample *build.Build
+		buildRuns   []build.BuildRun
+		deleted     []string
+		now         time.Time
+	)
+
+	// newBuildRun returns a BuildRun of the Build that completed the given time ago, a running
+	// BuildRun has no completion time and no status of its Succeeded condition
+	newBuildRun := func(name string, status corev1.ConditionStatus, completedAgo time.Duration) build.BuildRun {
+		buildRun := build.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{build.LabelBuild: "taxi"},
+			},
+		}
+		if status != corev1.ConditionUnknown {
+			buildRun.Status.CompletionTime = &metav1.Time{Time: now.Add(-completedAgo)}
+			buildRun.Status.SetCondition(&corev1alpha1.Condition{
+				Type:   corev1alpha1.ConditionSucceeded,
+				Status: status,
+			})
+		}
+		return buildRun
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		deleted = nil
+
+		buildSample = &build.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "taxi", Namespace: "default"},
+			Spec: build.BuildSpec{
+				Retention: &build.BuildRetention{},
+			},
+		}
+		buildRuns = []build.BuildRun{
+			newBuildRun("taxi-1", corev1.ConditionTrue, 5*time.Hour),
+			newBuildRun("taxi-2", corev1.ConditionFalse, 4*time.Hour),
+			newBuildRun("taxi-3", corev1.ConditionTrue, 3*time.Hour),
+			newBuildRun("taxi-4", corev1.ConditionFalse, 2*time.Hour),
+			newBuildRun("taxi-5", corev1.ConditionTrue, time.Hour),
+			newBuildRun("taxi-6", corev1.ConditionUnknown, 0),
+		}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "taxi", Namespace: "default"}}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			if b, ok := object.(*build.Build); ok && nn.Name == buildSample.Name {
+				buildSample.DeepCopyInto(b)
+				return nil
+			}
+			return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(_ context.Context, list runtime.Object, _ ...crc.ListOption) error {
+			list.(*build.BuildRunList).Items = buildRuns
+			return nil
+		})
+		client.DeleteCalls(func(_ context.Context, object runtime.Object, _ ...crc.DeleteOption) error {
+			deleted = append(deleted, object.(*build.BuildRun).Name)
+			return nil
+		})
+
+		manager = &fakes.FakeManager{}
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		testCtx := ctxlog.NewContext(context.TODO(), "fake-logger")
+		reconciler = buildretention.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
+	})
+
+	It("deletes nothing if the Build has no retention", func() {
+		buildSample.Spec.Retention = nil
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(client.ListCallCount()).To(Equal(0))
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("deletes nothing if the Build does not exist", func() {
+		buildSample.Name = "bus"
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("lists the BuildRuns of the Build by their label", func() {
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _, options := client.ListArgsForCall(0)
+		listOptions := &crc.ListOptions{}
+		listOptions.ApplyOptions(options)
+		Expect(listOptions.Namespace).To(Equal("default"))
+		Expect(listOptions.LabelSelector.String()).To(Equal(build.LabelBuild + "=taxi"))
+	})
+
+	It("deletes the oldest succeeded BuildRuns beyond the limit", func() {
+		limit := int32(1)
+		buildSample.Spec.Retention.SucceededLimit = &limit
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(ConsistOf("taxi-1", "taxi-3"))
+	})
+
+	It("deletes the oldest failed BuildRuns beyond the limit", func() {
+		limit := int32(0)
+		buildSample.Spec.Retention.FailedLimit = &limit
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(ConsistOf("taxi-2", "taxi-4"))
+	})
+
+	It("deletes the BuildRuns that completed longer ago than the time to live and requeues at the next expiry", func() {
+		buildSample.Spec.Retention.TTLAfterFinished = &metav1.Duration{Duration: 150 * time.Minute}
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(ConsistOf("taxi-1", "taxi-2", "taxi-3"))
+		Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+	})
+
+	It("applies the limits and the time to live together", func() {
+		succeededLimit, failedLimit := int32(1), int32(1)
+		buildSample.Spec.Retention.SucceededLimit = &succeededLimit
+		buildSample.Spec.Retention.FailedLimit = &failedLimit
+		buildSample.Spec.Retention.TTLAfterFinished = &metav1.Duration{Duration: 90 * time.Minute}
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(ConsistOf("taxi-1", "taxi-2", "taxi-3", "taxi-4"))
+	})
+
+	It("never deletes a BuildRun that did not complete", func() {
+		limit := int32(0)
+		buildSample.Spec.Retention.SucceededLimit = &limit
+		buildSample.Spec.Retention.FailedLimit = &limit
+		buildSample.Spec.Retention.TTLAfterFinished = &metav1.Duration{}
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(HaveLen(5))
+		Expect(deleted).ToNot(ContainElement("taxi-6"))
+	})
+
+	It("ignores BuildRuns that are already deleted", func() {
+		client.DeleteCalls(func(_ context.Context, object runtime.Object, _ ...crc.DeleteOption) error {
+			return k8serrors.NewNotFound(schema.GroupResource{}, object.(*build.BuildRun).Name)
+		})
+		limit := int32(0)
+		buildSample.Spec.Retention.SucceededLimit = &limit
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(3))
+	})
+})
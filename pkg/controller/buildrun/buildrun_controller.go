@@ -12,9 +12,11 @@ import (
 	"strings"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/ctxlog"
 	buildmetrics "github.com/shipwright-io/build/pkg/metrics"
+	"github.com/shipwright-io/build/pkg/registry"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,7 +38,6 @@ import (
 const (
 	namespace          string = "namespace"
 	name               string = "name"
-	pendingReason      string = "Pending"
 	generatedNameRegex        = "-[a-z0-9]{5,5}$"
 )
 
@@ -66,6 +67,7 @@ type ReconcileBuildRun struct {
 	client                client.Client
 	scheme                *runtime.Scheme
 	setOwnerReferenceFunc setOwnerReferenceFunc
+	registry              *registry.Resolver
 }
 
 // NewReconciler returns a new reconcile.Reconciler
@@ -76,6 +78,7 @@ func NewReconciler(ctx context.Context, c *config.Config, mgr manager.Manager, o
 		client:                mgr.GetClient(),
 		scheme:                mgr.GetScheme(),
 		setOwnerReferenceFunc: ownerRef,
+		registry:              registry.NewResolver(nil),
 	}
 }
 
@@ -102,6 +105,12 @@ func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler) error
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// Ignore updates to CR status in which case metadata.Generation does not change
 			o := e.ObjectOld.(*buildv1alpha1.BuildRun)
+			n := e.ObjectNew.(*buildv1alpha1.BuildRun)
+
+			// The cancellation of a BuildRun is reconciled also if it already has a TaskRun
+			if !o.Spec.IsCancelled() && n.Spec.IsCancelled() {
+				return true
+			}
 
 			// Avoid reconciling when for updates on the BuildRun, the build.build.dev/name
 			// label is set, and when a BuildRun already have a referenced TaskRun.
@@ -184,15 +193,16 @@ func handleError(message string, listOfErrors ...error) error {
 	return fmt.Errorf("errors: %s, msg: %s", strings.Join(errSlice, ", "), message)
 }
 
-// ValidateBuildRegistration verifies that a referenced Build is properly registered
+// ValidateBuildRegistration verifies that the Ready condition of a referenced Build is True for its current generation
 func (r *ReconcileBuildRun) ValidateBuildRegistration(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) error {
-	if build.Status.Registered == "" {
+	ready := build.Status.GetCondition(buildv1alpha1.BuildConditionReady)
+	if ready == nil || build.Status.ObservedGeneration != build.Generation {
 		err := fmt.Errorf("The Build is not yet validated, build: %s", build.Name)
 		return err
 	}
-	if build.Status.Registered != corev1.ConditionTrue {
-		err := fmt.Errorf("The Build is not registered correctly, build: %s, registered status: %s, reason: %s", build.Name, build.Status.Registered, build.Status.Reason)
-		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+	if !ready.IsTrue() {
+		err := fmt.Errorf("The Build is not ready, build: %s, reason: %s, message: %s", build.Name, ready.Reason, ready.Message)
+		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonBuildRegistrationFailed, err.Error())
 		return handleError("Build is not ready", err, updateErr)
 	}
 	return nil
@@ -231,7 +241,7 @@ func (r *ReconcileBuildRun) VerifyRequestName(ctx context.Context, request recon
 				// We ignore the errors from the following call, because the parent call of this function will always
 				// return back a reconcile.Result{}, nil. This is done to avoid infinite reconcile loops when a BuildRun
 				// does not longer exists
-				r.updateBuildRunErrorStatus(ctx, buildRun, fmt.Sprintf("taskRun %s doesn´t exist", request.Name))
+				r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunNotFound, fmt.Sprintf("taskRun %s doesn´t exist", request.Name))
 			}
 		}
 	}
@@ -266,9 +276,13 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 				return reconcile.Result{}, nil
 			}
 
+			if buildRun.Spec.IsCancelled() {
+				return reconcile.Result{}, r.cancelBuildRun(ctx, buildRun)
+			}
+
 			build = &buildv1alpha1.Build{}
 			if err = r.GetBuildObject(ctx, buildRun.Spec.BuildRef.Name, buildRun.Namespace, build); err != nil {
-				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonBuildNotFound, err.Error())
 				return reconcile.Result{}, handleError("Failed to fetch the Build instance", err, updateErr)
 			}
 
@@ -292,13 +306,28 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 				}
 			}
 
-			// Set the Build spec in the BuildRun status
-			buildRun.Status.BuildSpec = &build.Spec
+			// Apply the concurrency policy of the Build, a BuildRun that may not start yet waits without a TaskRun
+			if start, result, err := r.enforceConcurrencyPolicy(ctx, build, buildRun); !start || err != nil {
+				return result, err
+			}
+
+			// Apply the ClusterBuildRunQuotas, a BuildRun beyond their limits is queued without a TaskRun
+			if admitted, result, err := r.admitBuildRun(ctx, buildRun); !admitted || err != nil {
+				return result, err
+			}
+
+			// Set the Build spec, merged with the BuildRun overrides, in the BuildRun status
+			buildRun.Status.BuildSpec = &applyBuildRunOverrides(build, buildRun).Spec
 			ctxlog.Info(ctx, "updating BuildRun status", namespace, request.Namespace, name, request.Name)
 			if err = r.client.Status().Update(ctx, buildRun); err != nil {
 				return reconcile.Result{}, err
 			}
 
+			// A Build with platforms has a TaskRun for every platform
+			if hasPlatforms(buildRun) {
+				return reconcile.Result{}, r.createPlatformTaskRuns(ctx, build, buildRun)
+			}
+
 			// Create the TaskRun, this needs to be the last step in this block to be idempotent
 			generatedTaskRun, err := r.createTaskRun(ctx, build, buildRun)
 			if err != nil {
@@ -307,12 +336,13 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 
 			ctxlog.Info(ctx, "creating TaskRun from BuildRun", namespace, request.Namespace, name, generatedTaskRun.GenerateName, "BuildRun", buildRun.Name)
 			if err = r.client.Create(ctx, generatedTaskRun); err != nil {
-				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunCreationFailed, err.Error())
 				return reconcile.Result{}, handleError("Failed to create TaskRun if no TaskRun for that BuildRun exists", err, updateErr)
 			}
 
-			// Set the LastTaskRunRef in the BuildRun status
-			buildRun.Status.LatestTaskRunRef = &generatedTaskRun.Name
+			// Set the LastTaskRunRef in the BuildRun status, the BuildRun is pending until the TaskRun starts
+			recordTaskRun(buildRun, generatedTaskRun.Name)
+			buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, fmt.Sprintf("TaskRun %s is created", generatedTaskRun.Name))
 			ctxlog.Info(ctx, "updating BuildRun status with TaskRun name", namespace, request.Namespace, name, request.Name, "TaskRun", generatedTaskRun.Name)
 			if err = r.client.Status().Update(ctx, buildRun); err != nil {
 				// we ignore the error here to prevent another reconciliation that would create another TaskRun,
@@ -342,31 +372,72 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 			return reconcile.Result{}, nil
 		}
 
+		// Events of the TaskRuns of previous attempts do not change the BuildRun anymore
+		if isPreviousAttempt(buildRun, lastTaskRun.Name) {
+			ctxlog.Info(ctx, "TaskRun of a previous attempt of the BuildRun", namespace, request.Namespace, name, request.Name)
+			return reconcile.Result{}, nil
+		}
+
+		if buildRun.Spec.IsCancelled() {
+			return reconcile.Result{}, r.cancelBuildRun(ctx, buildRun)
+		}
+
+		// The status of a BuildRun with platforms depends on the TaskRuns of all platforms
+		if isPlatformTaskRun(lastTaskRun) {
+			return r.reconcilePlatformTaskRuns(ctx, buildRun)
+		}
+
 		trCondition := lastTaskRun.Status.GetCondition(apis.ConditionSucceeded)
 		if trCondition != nil {
+			// A failed TaskRun is retried before the BuildRun completes, so that the generated
+			// service account is still available
+			if isRetriable(ctx, buildRun, lastTaskRun, trCondition) {
+				recordTaskRun(buildRun, lastTaskRun.Name)
+				return r.retryTaskRun(ctx, buildRun, lastTaskRun, trCondition)
+			}
+
 			taskRunStatus := trCondition.Status
 			// check if we should delete the generated service account by checking the build run spec and that the task run is complete
-			if isGeneratedServiceAccountUsed(buildRun) && (taskRunStatus == corev1.ConditionTrue || taskRunStatus == corev1.ConditionFalse) {
-				serviceAccount := &corev1.ServiceAccount{}
-				serviceAccount.Name = getGeneratedServiceAccountName(buildRun)
-				serviceAccount.Namespace = buildRun.Namespace
-
-				ctxlog.Info(ctx, "deleting service account", namespace, request.Namespace, name, request.Name)
-				if err = r.client.Delete(ctx, serviceAccount); err != nil && !apierrors.IsNotFound(err) {
-					ctxlog.Error(ctx, err, "Error during deletion of generated service account.")
+			if taskRunStatus == corev1.ConditionTrue || taskRunStatus == corev1.ConditionFalse {
+				if err = r.deleteGeneratedServiceAccount(ctx, buildRun); err != nil {
 					return reconcile.Result{}, err
 				}
 			}
 
-			buildRun.Status.Succeeded = taskRunStatus
-			if taskRunStatus == corev1.ConditionFalse {
-				buildRun.Status.Reason = trCondition.Message
-			} else {
-				buildRun.Status.Reason = trCondition.Reason
+			var previousReason string
+			if previous := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded); previous != nil {
+				previousReason = previous.Reason
+			}
+
+			reason, message := getTaskRunReasonAndMessage(ctx, lastTaskRun, trCondition)
+			buildRun.Status.SetSucceededCondition(taskRunStatus, reason, message)
+
+			if taskRunStatus == corev1.ConditionTrue || taskRunStatus == corev1.ConditionFalse {
+				updateBuildRunSources(ctx, buildRun, lastTaskRun)
+				updateBuildRunVulnerabilities(ctx, buildRun, lastTaskRun)
+			}
+			if taskRunStatus == corev1.ConditionTrue {
+				updateBuildRunOutput(ctx, buildRun, lastTaskRun)
+
+				// the BuildRun fails if its image cannot be pushed under all of its tags
+				if err := r.tagOutputImage(ctx, buildRun); err != nil {
+					ctxlog.Error(ctx, err, "failed to tag the output image", namespace, buildRun.Namespace, name, buildRun.Name)
+					taskRunStatus, reason, message = corev1.ConditionFalse, buildv1alpha1.BuildRunReasonTaggingFailed, err.Error()
+					buildRun.Status.SetSucceededCondition(taskRunStatus, reason, message)
+				} else if err := r.attestOutputImage(ctx, buildRun, lastTaskRun); err != nil {
+					ctxlog.Error(ctx, err, "failed to push the provenance of the output image", namespace, buildRun.Namespace, name, buildRun.Name)
+					taskRunStatus, reason, message = corev1.ConditionFalse, buildv1alpha1.BuildRunReasonProvenanceFailed, err.Error()
+					buildRun.Status.SetSucceededCondition(taskRunStatus, reason, message)
+				}
+				updateBuildRunSignature(buildRun)
+				updateBuildRunSBOM(buildRun)
 			}
 
-			buildRun.Status.LatestTaskRunRef = &lastTaskRun.Name
-			buildRun.Status.StartTime = lastTaskRun.Status.StartTime
+			recordTaskRun(buildRun, lastTaskRun.Name)
+			// the BuildRun started with the TaskRun of its first attempt
+			if buildRun.Status.StartTime == nil || buildRun.Status.Attempts <= 1 {
+				buildRun.Status.StartTime = lastTaskRun.Status.StartTime
+			}
 			if lastTaskRun.Status.CompletionTime != nil && buildRun.Status.CompletionTime == nil {
 				buildRun.Status.CompletionTime = lastTaskRun.Status.CompletionTime
 
@@ -428,6 +499,11 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 			if err = r.client.Status().Update(ctx, buildRun); err != nil {
 				return reconcile.Result{}, err
 			}
+
+			// Report the commit status once per change of the reason, after the BuildRun status is stored
+			if state, ok := getCommitStatusState(taskRunStatus, reason); ok && reason != previousReason {
+				r.reportCommitStatus(ctx, buildRun, state, reason)
+			}
 		}
 	}
 
@@ -521,54 +597,144 @@ func (r *ReconcileBuildRun) retrieveClusterBuildStrategy(ctx context.Context, bu
 func (r *ReconcileBuildRun) createTaskRun(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (*v1beta1.TaskRun, error) {
 	var generatedTaskRun *v1beta1.TaskRun
 	// Choose a service account to use
-	serviceAccount, err := r.retrieveServiceAccount(ctx, build, buildRun)
+	serviceAccount, err := r.retrieveServiceAccount(ctx, applyBuildRunOverrides(build, buildRun), buildRun)
 	if err != nil {
-		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonServiceAccountNotFound, err.Error())
 		return nil, handleError("Failed to choose a service account to use", err, updateErr)
 	}
 
 	if build.Spec.StrategyRef.Kind == nil || *build.Spec.StrategyRef.Kind == buildv1alpha1.NamespacedBuildStrategyKind {
 		buildStrategy, err := r.retrieveBuildStrategy(ctx, build)
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonStrategyNotFound, err.Error())
+				return nil, handleError("Failed to retrieve the buildStrategy", err, updateErr)
+			}
 			return nil, err
 		}
 		if buildStrategy != nil {
-			generatedTaskRun, err = GenerateTaskRun(r.config, build, buildRun, serviceAccount.Name, buildStrategy.Spec.BuildSteps)
+			generatedTaskRun, err = GenerateTaskRun(r.config, build, buildRun, serviceAccount.Name, buildStrategy)
 			if err != nil {
-				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, err.Error())
 				return nil, handleError("Failed to generate the taskrun with buildStrategy", err, updateErr)
 			}
 		}
 	} else if *build.Spec.StrategyRef.Kind == buildv1alpha1.ClusterBuildStrategyKind {
 		clusterBuildStrategy, err := r.retrieveClusterBuildStrategy(ctx, build)
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonStrategyNotFound, err.Error())
+				return nil, handleError("Failed to retrieve the clusterBuildStrategy", err, updateErr)
+			}
 			return nil, err
 		}
 		if clusterBuildStrategy != nil {
-			generatedTaskRun, err = GenerateTaskRun(r.config, build, buildRun, serviceAccount.Name, clusterBuildStrategy.Spec.BuildSteps)
+			generatedTaskRun, err = GenerateTaskRun(r.config, build, buildRun, serviceAccount.Name, clusterBuildStrategy)
 			if err != nil {
-				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+				updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, err.Error())
 				return nil, handleError("Failed to generate the taskrun with clusterBuildStrategy", err, updateErr)
 			}
 		}
 	} else {
 		err := fmt.Errorf("unknown strategy %s", string(*build.Spec.StrategyRef.Kind))
-		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonUnknownStrategyKind, err.Error())
 		return nil, handleError(fmt.Sprintf("Unsupported BuildStrategy Kind: %v", build.Spec.StrategyRef.Kind), err, updateErr)
 	}
 
 	// Set OwnerReference for BuildRun and TaskRun
 	if err := r.setOwnerReferenceFunc(buildRun, generatedTaskRun, r.scheme); err != nil {
-		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, err.Error())
+		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunCreationFailed, err.Error())
 		return nil, handleError("Failed to set OwnerReference for BuildRun and TaskRun", err, updateErr)
 	}
 
 	return generatedTaskRun, nil
 }
 
-func (r *ReconcileBuildRun) updateBuildRunErrorStatus(ctx context.Context, buildRun *buildv1alpha1.BuildRun, errorMessage string) error {
-	buildRun.Status.Succeeded = corev1.ConditionFalse
-	buildRun.Status.Reason = errorMessage
+// cancelBuildRun cancels the TaskRun of the BuildRun if it is still running, and completes the
+// BuildRun with the Cancelled reason. The generated service account is deleted like for every
+// completed BuildRun. A BuildRun that already completed is not changed.
+func (r *ReconcileBuildRun) cancelBuildRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun) error {
+	if buildRun.Status.CompletionTime != nil {
+		ctxlog.Info(ctx, "BuildRun already completed, it is not cancelled", namespace, buildRun.Namespace, name, buildRun.Name)
+		return nil
+	}
+
+	// a BuildRun with platforms has a TaskRun for every platform
+	var taskRunNames []string
+	if buildRun.Status.LatestTaskRunRef != nil {
+		taskRunNames = append(taskRunNames, *buildRun.Status.LatestTaskRunRef)
+	}
+	for _, platform := range buildRun.Status.Platforms {
+		if platform.TaskRunRef != "" && (buildRun.Status.LatestTaskRunRef == nil || platform.TaskRunRef != *buildRun.Status.LatestTaskRunRef) {
+			taskRunNames = append(taskRunNames, platform.TaskRunRef)
+		}
+	}
+
+	for _, taskRunName := range taskRunNames {
+		taskRun := &v1beta1.TaskRun{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: taskRunName, Namespace: buildRun.Namespace}, taskRun)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		if err == nil {
+			if err := r.cancelTaskRun(ctx, buildRun, taskRun); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.deleteGeneratedServiceAccount(ctx, buildRun); err != nil {
+		return err
+	}
+
+	ctxlog.Info(ctx, "BuildRun is cancelled", namespace, buildRun.Namespace, name, buildRun.Name)
+	if err := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonCancelled, "the BuildRun is cancelled"); err != nil {
+		return err
+	}
+
+	if state, ok := getCommitStatusState(corev1.ConditionFalse, buildv1alpha1.BuildRunReasonCancelled); ok {
+		r.reportCommitStatus(ctx, buildRun, state, buildv1alpha1.BuildRunReasonCancelled)
+	}
+	return nil
+}
+
+// cancelTaskRun cancels the TaskRun of the BuildRun, unless it is already done or cancelled
+func (r *ReconcileBuildRun) cancelTaskRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun) error {
+	if taskRun.IsDone() || taskRun.IsCancelled() {
+		return nil
+	}
+
+	patch := client.MergeFrom(taskRun.DeepCopy())
+	taskRun.Spec.Status = v1beta1.TaskRunSpecStatusCancelled
+
+	ctxlog.Info(ctx, "cancelling TaskRun", namespace, buildRun.Namespace, name, buildRun.Name, "TaskRun", taskRun.Name)
+	if err := r.client.Patch(ctx, taskRun, patch); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteGeneratedServiceAccount deletes the service account that was generated for the BuildRun, if any
+func (r *ReconcileBuildRun) deleteGeneratedServiceAccount(ctx context.Context, buildRun *buildv1alpha1.BuildRun) error {
+	if !isGeneratedServiceAccountUsed(buildRun) {
+		return nil
+	}
+
+	serviceAccount := &corev1.ServiceAccount{}
+	serviceAccount.Name = getGeneratedServiceAccountName(buildRun)
+	serviceAccount.Namespace = buildRun.Namespace
+
+	ctxlog.Info(ctx, "deleting service account", namespace, buildRun.Namespace, name, buildRun.Name)
+	if err := r.client.Delete(ctx, serviceAccount); err != nil && !apierrors.IsNotFound(err) {
+		ctxlog.Error(ctx, err, "Error during deletion of generated service account.")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileBuildRun) updateBuildRunErrorStatus(ctx context.Context, buildRun *buildv1alpha1.BuildRun, reason string, errorMessage string) error {
+	buildRun.Status.SetSucceededCondition(corev1.ConditionFalse, reason, errorMessage)
 	now := metav1.Now()
 	buildRun.Status.CompletionTime = &now
 	ctxlog.Debug(ctx, "updating buildRun status", namespace, buildRun.Namespace, name, buildRun.Name)
@@ -576,6 +742,35 @@ func (r *ReconcileBuildRun) updateBuildRunErrorStatus(ctx context.Context, build
 	return updateErr
 }
 
+// getSucceededConditionReasonAndMessage maps the Succeeded condition of a TaskRun to the reason
+// and message of the Succeeded condition of its BuildRun
+func getSucceededConditionReasonAndMessage(trCondition *apis.Condition) (string, string) {
+	switch trCondition.Status {
+	case corev1.ConditionTrue:
+		return buildv1alpha1.BuildRunReasonSucceeded, trCondition.Message
+
+	case corev1.ConditionFalse:
+		switch trCondition.Reason {
+		case v1beta1.TaskRunReasonTimedOut.String():
+			return buildv1alpha1.BuildRunReasonTimeout, trCondition.Message
+		case v1beta1.TaskRunReasonCancelled.String():
+			return buildv1alpha1.BuildRunReasonCancelled, trCondition.Message
+		default:
+			return buildv1alpha1.BuildRunReasonFailed, trCondition.Message
+		}
+
+	default:
+		switch trCondition.Reason {
+		case v1beta1.TaskRunReasonStarted.String(), buildv1alpha1.BuildRunReasonPending:
+			return buildv1alpha1.BuildRunReasonPending, trCondition.Message
+		case v1beta1.TaskRunReasonRunning.String():
+			return buildv1alpha1.BuildRunReasonRunning, trCondition.Message
+		default:
+			return trCondition.Reason, trCondition.Message
+		}
+	}
+}
+
 // getGeneratedServiceAccountName returns the name of the generated service account for a build run
 func getGeneratedServiceAccountName(buildRun *buildv1alpha1.BuildRun) string {
 	return buildRun.Name + "-sa"
@@ -6,14 +6,20 @@ package buildrun_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/shipwright-io/build/pkg/apis"
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	buildrunctl "github.com/shipwright-io/build/pkg/controller/buildrun"
 	"github.com/shipwright-io/build/pkg/controller/fakes"
@@ -317,15 +323,73 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(client.StatusCallCount()).To(Equal(1))
 			})
 
+			It("records the image digest and size that the strategy reported", func() {
+				buildSample.Spec.Output.ImageURL = "quay.io/foobar/app:latest"
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-image-digest", Value: "sha256:2d4a2f4e8b4b6c5a0d1a3e9d6f0a3b1c2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f80\n"},
+					{Name: "shp-image-size", Value: "53728"},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := object.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.Output).To(Equal(&build.BuildRunOutput{
+						Image:  "quay.io/foobar/app:latest",
+						Digest: "sha256:2d4a2f4e8b4b6c5a0d1a3e9d6f0a3b1c2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f80",
+						Size:   53728,
+					}))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
+			It("records the commit that the TaskRun checked out", func() {
+				revision := "feature-branch"
+				buildSample.Spec.Source.Revision = &revision
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+				taskRunSample = ctl.DefaultTaskRunWithFalseStatus(taskRunName, buildRunName, ns)
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-commit-sha", Value: "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5\n"},
+					{Name: "shp-source-commit-author", Value: "Jane Doe\n"},
+					{Name: "shp-source-commit-timestamp", Value: "2020-10-07T10:30:00+02:00\n"},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := object.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.Sources).To(HaveLen(1))
+					Expect(buildRun.Status.Sources[0].Name).To(Equal("default"))
+
+					git := buildRun.Status.Sources[0].Git
+					Expect(git).ToNot(BeNil())
+					Expect(git.CommitSha).To(Equal("a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"))
+					Expect(git.CommitAuthor).To(Equal("Jane Doe"))
+					Expect(git.CommitTimestamp.Time.UTC()).To(Equal(time.Date(2020, 10, 7, 8, 30, 0, 0, time.UTC)))
+					Expect(git.Branch).To(Equal("feature-branch"))
+
+					// the image is only recorded for a successful TaskRun
+					Expect(buildRun.Status.Output).To(BeNil())
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
 			It("updates the BuildRun status when a FALSE status occurs", func() {
 
 				taskRunSample = ctl.DefaultTaskRunWithFalseStatus(taskRunName, buildRunName, ns)
 
 				// Based on the current buildRun controller, if the TaskRun condition.Status
-				// is FALSE, we will then populate our buildRun.Status.Reason with the
-				// TaskRun condition.Message, rather than the condition.Reason
+				// is FALSE, we will then populate our buildRun Succeeded condition with the
+				// Failed reason and the TaskRun condition.Message
 				statusCall := ctl.StubBuildRunStatus(
-					"some message",
+					"Failed",
 					&taskRunName,
 					corev1.ConditionFalse,
 					buildSample.Spec,
@@ -338,6 +402,68 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(reconcile.Result{}).To(Equal(result))
 			})
 
+			It("updates the BuildRun status with a Timeout reason when the TaskRun timed out", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "TaskRunTimeout")
+
+				statusCall := ctl.StubBuildRunStatus(
+					"Timeout",
+					&taskRunName,
+					corev1.ConditionFalse,
+					buildSample.Spec,
+					false,
+				)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
+			It("updates the BuildRun status with a Cancelled reason when the TaskRun was cancelled", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "TaskRunCancelled")
+
+				statusCall := ctl.StubBuildRunStatus(
+					"Cancelled",
+					&taskRunName,
+					corev1.ConditionFalse,
+					buildSample.Spec,
+					false,
+				)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
+			It("keeps the TaskRun message and the transition time of an unchanged condition and sets the severity to Error", func() {
+				taskRunSample = ctl.DefaultTaskRunWithFalseStatus(taskRunName, buildRunName, ns)
+
+				transitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+				buildRunSample.Status.Conditions = corev1alpha1.Conditions{{
+					Type:               corev1alpha1.ConditionSucceeded,
+					Status:             corev1.ConditionFalse,
+					Reason:             "Failed",
+					Severity:           corev1alpha1.ConditionSeverityWarning,
+					LastTransitionTime: corev1alpha1.VolatileTime{Inner: transitionTime},
+				}}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := object.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.Conditions).To(HaveLen(1))
+					condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+					Expect(condition.Message).To(Equal("some message"))
+					Expect(condition.Severity).To(Equal(corev1alpha1.ConditionSeverityError))
+					Expect(condition.LastTransitionTime.Inner).To(Equal(transitionTime))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
 			It("does not break the reconcile when a taskrun pod initcontainers are not ready", func() {
 				taskRunSample = ctl.TaskRunWithCompletionAndStartTime(taskRunName, buildRunName, ns)
 
@@ -364,6 +490,430 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(client.GetCallCount()).To(Equal(3))
 			})
 		})
+		Context("reporting the commit status", func() {
+			const sha = "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"
+
+			var (
+				server   *httptest.Server
+				requests []string
+				states   []string
+			)
+
+			BeforeEach(func() {
+				requests, states = nil, nil
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					if r.Method == http.MethodPost {
+						var body map[string]string
+						Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+						states = append(states, body["state"])
+					}
+					w.Write([]byte(`{"sha": "` + sha + `"}`))
+				}))
+
+				taskRunRequest = newReconcileRequest(taskRunName, ns)
+
+				buildSample.Spec.Source.URL = server.URL + "/owner/repo"
+				buildSample.Spec.Source.Flavor = "github"
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("reports the checked out commit when the TaskRun succeeds", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded")
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-commit-sha", Value: sha},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(Equal([]string{"POST /api/v3/repos/owner/repo/statuses/" + sha}))
+				Expect(states).To(Equal([]string{"success"}))
+			})
+
+			It("resolves the revision when the TaskRun starts running", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionUnknown, "Running")
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(Equal([]string{
+					"GET /api/v3/repos/owner/repo/commits/master",
+					"POST /api/v3/repos/owner/repo/statuses/" + sha,
+				}))
+				Expect(states).To(Equal([]string{"pending"}))
+			})
+
+			It("stores the resolved commit for the later reports", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionUnknown, "Running")
+
+				var stored []string
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					stored = append(stored, object.(*build.BuildRun).Status.CommitStatusSha)
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored).To(Equal([]string{"", sha}))
+			})
+
+			It("reports to the stored commit even if another commit was checked out", func() {
+				const otherSha = "0123456789abcdef0123456789abcdef01234567"
+				buildRunSample.Status.CommitStatusSha = sha
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded")
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-commit-sha", Value: otherSha},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(Equal([]string{"POST /api/v3/repos/owner/repo/statuses/" + sha}))
+				Expect(states).To(Equal([]string{"success"}))
+			})
+
+			It("authenticates with the password of the source secret", func() {
+				buildSample.Spec.Source.SecretRef = &corev1.LocalObjectReference{Name: "git-token"}
+				taskRunSample = ctl.DefaultTaskRunWithFalseStatus(taskRunName, buildRunName, ns)
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-commit-sha", Value: sha},
+				}
+
+				var authorization string
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					authorization = r.Header.Get("Authorization")
+					w.Write([]byte("{}"))
+				})
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					if secret, ok := object.(*corev1.Secret); ok {
+						Expect(nn.Name).To(Equal("git-token"))
+						secret.Data = map[string][]byte{
+							corev1.BasicAuthUsernameKey: []byte("jane"),
+							corev1.BasicAuthPasswordKey: []byte("secret-token"),
+						}
+						return nil
+					}
+					return getClientStub(context, nn, object)
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(authorization).To(Equal("token secret-token"))
+			})
+
+			It("does not report again if the reason did not change", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionUnknown, "Running")
+				buildRunSample.Status.SetSucceededCondition(corev1.ConditionUnknown, build.BuildRunReasonRunning, "")
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(BeEmpty())
+			})
+
+			It("does not report for the generic flavor", func() {
+				buildSample.Spec.Source.Flavor = "generic"
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded")
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(BeEmpty())
+			})
+		})
+
+		Context("tagging the output image", func() {
+			const (
+				sha    = "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"
+				digest = "sha256:2d4a2f4e8b4b6c5a0d1a3e9d6f0a3b1c2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f80"
+			)
+
+			var (
+				server   *httptest.Server
+				host     string
+				requests []string
+				buildRun *build.BuildRun
+			)
+
+			BeforeEach(func() {
+				requests, buildRun = nil, nil
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					if r.Method == http.MethodPut {
+						Expect(r.Header.Get("Content-Type")).To(Equal("application/vnd.docker.distribution.manifest.v2+json"))
+						w.WriteHeader(http.StatusCreated)
+						return
+					}
+					w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+					w.Write([]byte(`{"schemaVersion": 2}`))
+				}))
+				host = server.Listener.Addr().String()
+
+				taskRunRequest = newReconcileRequest(taskRunName, ns)
+
+				buildSample.Spec.Output.ImageURL = host + "/foobar/app:latest"
+				buildSample.Spec.Output.Tags = []string{"{{.Revision.ShortSHA}}", "{{.BuildRun.Name}}", "latest"}
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded")
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-image-digest", Value: digest},
+					{Name: "shp-source-commit-sha", Value: sha},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun = object.(*build.BuildRun).DeepCopy()
+					return nil
+				})
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("pushes the image under all tags and records their references", func() {
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(Equal([]string{
+					"GET /v2/foobar/app/manifests/" + digest,
+					"PUT /v2/foobar/app/manifests/a8b3c2d",
+					"PUT /v2/foobar/app/manifests/" + buildRunName,
+					"PUT /v2/foobar/app/manifests/latest",
+				}))
+
+				Expect(buildRun).ToNot(BeNil())
+				Expect(buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Status).To(Equal(corev1.ConditionTrue))
+				Expect(buildRun.Status.Output.Images).To(Equal([]string{
+					host + "/foobar/app:latest",
+					host + "/foobar/app:a8b3c2d",
+					host + "/foobar/app:" + buildRunName,
+				}))
+			})
+
+			It("replaces the characters of the branch that are not allowed in tags", func() {
+				revision := "feature/x"
+				buildSample.Spec.Source.Revision = &revision
+				buildSample.Spec.Output.Tags = []string{"{{.Revision.Branch}}"}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(ContainElement("PUT /v2/foobar/app/manifests/feature-x"))
+				Expect(buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Status).To(Equal(corev1.ConditionTrue))
+				Expect(buildRun.Status.Output.Images).To(ContainElement(host + "/foobar/app:feature-x"))
+			})
+
+			It("resolves the digest of the image if the strategy did not report it", func() {
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-commit-sha", Value: sha},
+				}
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+					w.Header().Set("Docker-Content-Digest", digest)
+					w.Write([]byte(`{"schemaVersion": 2}`))
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests[0]).To(Equal("HEAD /v2/foobar/app/manifests/latest"))
+				Expect(buildRun.Status.Output.Digest).To(Equal(digest))
+				Expect(buildRun.Status.Output.Images).To(HaveLen(3))
+			})
+
+			It("fails the BuildRun with the TaggingFailed reason if the registry rejects a tag", func() {
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPut {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+					w.Write([]byte(`{"schemaVersion": 2}`))
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonTaggingFailed))
+				Expect(condition.Message).To(ContainSubstring("403 Forbidden"))
+				Expect(buildRun.Status.Output.Images).To(BeEmpty())
+			})
+
+			It("fails the BuildRun if a tag template results in an empty tag", func() {
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-image-digest", Value: digest},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requests).To(BeEmpty())
+
+				condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonTaggingFailed))
+				Expect(condition.Message).To(ContainSubstring("{{.Revision.ShortSHA}}"))
+			})
+
+			It("records the signature of the image if the Build signs its output", func() {
+				buildSample.Spec.Output.Signing = &build.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "signing-key"},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Status).To(Equal(corev1.ConditionTrue))
+				Expect(buildRun.Status.Output.Signature).To(Equal(host + "/foobar/app:sha256-" + strings.TrimPrefix(digest, "sha256:") + ".sig"))
+			})
+
+			It("records no signature if the Build does not sign its output", func() {
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buildRun.Status.Output.Signature).To(BeEmpty())
+			})
+
+			It("records the vulnerabilities of the image if it was scanned", func() {
+				taskRunSample.Status.TaskRunResults = append(taskRunSample.Status.TaskRunResults, v1beta1.TaskRunResult{
+					Name: "shp-vulnerabilities", Value: `{"critical":0,"high":0,"medium":3,"low":7,"unknown":1}`,
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Status).To(Equal(corev1.ConditionTrue))
+				Expect(buildRun.Status.Vulnerabilities).To(Equal(&build.VulnerabilitySummary{Medium: 3, Low: 7, Unknown: 1}))
+			})
+
+			It("records the SBOM of the image if the Build generates one", func() {
+				buildSample.Spec.Output.SBOM = &build.ImageSBOM{Format: build.SBOMFormatCycloneDX}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buildRun.Status.Output.SBOM).To(Equal(host + "/foobar/app:sha256-" + strings.TrimPrefix(digest, "sha256:") + ".sbom"))
+			})
+		})
+
+		Context("pushing the provenance of the output image", func() {
+			const (
+				sha    = "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"
+				digest = "sha256:2d4a2f4e8b4b6c5a0d1a3e9d6f0a3b1c2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f80"
+			)
+
+			var (
+				server    *httptest.Server
+				host      string
+				blobs     []string
+				manifests map[string]string
+				buildRun  *build.BuildRun
+			)
+
+			BeforeEach(func() {
+				blobs, manifests, buildRun = nil, map[string]string{}, nil
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, err := ioutil.ReadAll(r.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					switch {
+					case r.Method == http.MethodPost:
+						w.Header().Set("Location", "/v2/foobar/app/blobs/uploads/0123")
+						w.WriteHeader(http.StatusAccepted)
+					case strings.HasPrefix(r.URL.Path, "/v2/foobar/app/blobs/uploads/"):
+						blobs = append(blobs, string(body))
+						w.WriteHeader(http.StatusCreated)
+					default:
+						manifests[strings.TrimPrefix(r.URL.Path, "/v2/foobar/app/manifests/")] = string(body)
+						w.WriteHeader(http.StatusCreated)
+					}
+				}))
+				host = server.Listener.Addr().String()
+
+				taskRunRequest = newReconcileRequest(taskRunName, ns)
+
+				provenance := true
+				buildSample.Spec.Output.ImageURL = host + "/foobar/app:latest"
+				buildSample.Spec.Output.Provenance = &provenance
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded")
+				taskRunSample.Annotations = map[string]string{build.AnnotationBuildRunStrategyDigest: "sha256:0123"}
+				taskRunSample.Spec.Params = []v1beta1.Param{
+					{Name: "SOURCE_URL", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: buildSample.Spec.Source.URL}},
+				}
+				taskRunSample.Status.Steps = []v1beta1.StepState{
+					{Name: "build-and-push", ImageID: "docker-pullable://gcr.io/kaniko-project/executor@sha256:4567"},
+				}
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-image-digest", Value: digest},
+					{Name: "shp-source-commit-sha", Value: sha},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun = object.(*build.BuildRun).DeepCopy()
+					return nil
+				})
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("pushes the provenance of the image as attestation and records its reference", func() {
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				tag := "sha256-" + strings.TrimPrefix(digest, "sha256:") + ".provenance"
+				Expect(buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Status).To(Equal(corev1.ConditionTrue))
+				Expect(buildRun.Status.Output.Provenance).To(Equal(host + "/foobar/app:" + tag))
+				Expect(manifests).To(HaveKey(tag))
+				Expect(blobs).To(HaveLen(2))
+
+				statement := map[string]interface{}{}
+				Expect(json.Unmarshal([]byte(blobs[1]), &statement)).To(Succeed())
+				Expect(statement["subject"]).To(Equal([]interface{}{
+					map[string]interface{}{"name": host + "/foobar/app", "digest": map[string]interface{}{"sha256": strings.TrimPrefix(digest, "sha256:")}},
+				}))
+
+				predicate := statement["predicate"].(map[string]interface{})
+				Expect(predicate["invocation"]).To(HaveKeyWithValue("configSource", map[string]interface{}{
+					"uri":        "git+" + buildSample.Spec.Source.URL,
+					"digest":     map[string]interface{}{"sha1": sha},
+					"entryPoint": buildSample.Spec.StrategyRef.Name,
+				}))
+				Expect(predicate["invocation"]).To(HaveKeyWithValue("parameters", HaveKeyWithValue("SOURCE_URL", buildSample.Spec.Source.URL)))
+				Expect(predicate["buildConfig"]).To(HaveKeyWithValue("strategy", HaveKeyWithValue("digest", "sha256:0123")))
+				Expect(predicate["materials"]).To(ContainElement(map[string]interface{}{
+					"uri":    "oci://gcr.io/kaniko-project/executor@sha256:4567",
+					"digest": map[string]interface{}{"sha256": "4567"},
+				}))
+			})
+
+			It("fails the BuildRun with the ProvenanceFailed reason if the registry rejects the attestation", func() {
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonProvenanceFailed))
+				Expect(condition.Message).To(ContainSubstring("403 Forbidden"))
+				Expect(buildRun.Status.Output.Provenance).To(BeEmpty())
+			})
+
+			It("pushes no provenance if the Build does not enable it", func() {
+				buildSample.Spec.Output.Provenance = nil
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(manifests).To(BeEmpty())
+				Expect(buildRun.Status.Output.Provenance).To(BeEmpty())
+			})
+		})
+
 		Context("from an existing BuildRun resource", func() {
 			var (
 				saName           string
@@ -401,7 +951,7 @@ var _ = Describe("Reconcile BuildRun", func() {
 				// Stub that asserts the BuildRun status fields when
 				// Status updates for a BuildRun take place
 				statusCall := ctl.StubBuildRunStatus(
-					fmt.Sprintf(" \"%s\" not found", saName),
+					"ServiceAccountNotFound",
 					emptyTaskRunName,
 					corev1.ConditionFalse,
 					buildSample.Spec,
@@ -458,20 +1008,67 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf(" \"%s\" not found", strategyName)))
 			})
 
-			It("fails on a TaskRun creation due to missing cluster buildstrategy", func() {
-				// override the Build to use a cluster BuildStrategy
-				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
+			It("sets the StrategyNotFound reason when the buildstrategy is missing", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.NamespacedBuildStrategyKind)
 
-				// Override Stub get calls to include a service account
-				// but none BuildStrategy
 				client.GetCalls(ctl.StubBuildRunGetWithSA(
 					buildSample,
 					buildRunSample,
 					ctl.DefaultServiceAccount(saName)),
 				)
 
-				_, err := reconciler.Reconcile(buildRunRequest)
-				Expect(err).To(HaveOccurred())
+				statusCall := ctl.StubBuildRunStatus(
+					"StrategyNotFound",
+					emptyTaskRunName,
+					corev1.ConditionFalse,
+					buildSample.Spec,
+					true,
+				)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).To(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(2))
+			})
+
+			It("sets the BuildNotFound reason when the Build is missing", func() {
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.BuildRun:
+						buildRunSample.DeepCopyInto(object)
+						return nil
+					}
+					return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+				})
+
+				statusCall := ctl.StubBuildRunStatus(
+					"BuildNotFound",
+					emptyTaskRunName,
+					corev1.ConditionFalse,
+					buildSample.Spec,
+					false,
+				)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).To(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(1))
+			})
+
+			It("fails on a TaskRun creation due to missing cluster buildstrategy", func() {
+				// override the Build to use a cluster BuildStrategy
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
+
+				// Override Stub get calls to include a service account
+				// but none BuildStrategy
+				client.GetCalls(ctl.StubBuildRunGetWithSA(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName)),
+				)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf(" \"%s\" not found", strategyName)))
 			})
 
@@ -617,8 +1214,8 @@ var _ = Describe("Reconcile BuildRun", func() {
 				_, err := reconciler.Reconcile(buildRunRequest)
 				Expect(err).ToNot(HaveOccurred())
 			})
-			It("stops creation when a FALSE registered status of the build occurs", func() {
-				// Init the Build with registered status false
+			It("stops creation when a FALSE Ready condition of the build occurs", func() {
+				// Init the Build with a Ready condition that is false
 				buildSample = ctl.DefaultBuildWithFalseRegistered(buildName, strategyName, build.ClusterBuildStrategyKind)
 				getClientStub := func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
 					switch object := object.(type) {
@@ -635,14 +1232,26 @@ var _ = Describe("Reconcile BuildRun", func() {
 				client.GetCalls(getClientStub)
 				_, err := reconciler.Reconcile(buildRunRequest)
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("reason: something bad happened"))
+				Expect(err.Error()).To(ContainSubstring("message: something bad happened"))
 				Expect(client.StatusCallCount()).To(Equal(1))
 			})
 
-			It("delays creation if the registered status of the build is not yet set", func() {
+			It("delays creation if the Ready condition of the build is not yet set", func() {
 				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
-				buildSample.Status.Registered = ""
-				buildSample.Status.Reason = ""
+				buildSample.Status.Conditions = nil
+
+				client.GetCalls(ctl.StubBuildRunGetWithoutSA(buildSample, buildRunSample))
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("The Build is not yet validated, build: %s", buildName)))
+				Expect(client.StatusCallCount()).To(Equal(0))
+			})
+
+			It("delays creation if the Ready condition of the build belongs to an older generation", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
+				buildSample.Generation = 2
+				buildSample.Status.ObservedGeneration = 1
 
 				client.GetCalls(ctl.StubBuildRunGetWithoutSA(buildSample, buildRunSample))
 
@@ -684,6 +1293,996 @@ var _ = Describe("Reconcile BuildRun", func() {
 
 				Expect(client.CreateCallCount()).To(Equal(1))
 			})
+
+			It("sets the Pending reason after creating the TaskRun", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					switch object := object.(type) {
+					case *v1beta1.TaskRun:
+						object.Name = taskRunName
+					}
+					return nil
+				})
+
+				statusCall := ctl.StubBuildRunStatus(
+					"Pending",
+					&taskRunName,
+					corev1.ConditionUnknown,
+					buildSample.Spec,
+					true,
+				)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(2))
+			})
+
+			It("records the Build spec merged with the BuildRun overrides in the BuildRun status", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.NamespacedBuildStrategyKind)
+
+				revision := "feature-branch"
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Spec.Revision = &revision
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					switch object := object.(type) {
+					case *v1beta1.TaskRun:
+						ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionTrue, "Succeeded").DeepCopyInto(object)
+					}
+					return nil
+				})
+
+				expectedBuildSpec := buildSample.Spec.DeepCopy()
+				expectedBuildSpec.Source.Revision = &revision
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					switch object := object.(type) {
+					case *build.BuildRun:
+						Expect(object.Status.BuildSpec).To(Equal(expectedBuildSpec))
+					}
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(BeNumerically(">", 0))
+				Expect(buildSample.Spec.Source.Revision).To(BeNil())
+			})
+
+			It("keeps the tags of the Build output when the BuildRun overrides the output image", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.NamespacedBuildStrategyKind)
+				buildSample.Spec.Output.Tags = []string{"{{.Revision.ShortSHA}}"}
+
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Spec.Output = &build.Image{ImageURL: "quay.io/foobar/app:preview"}
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				var output *build.Image
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					if buildRun, ok := object.(*build.BuildRun); ok && buildRun.Status.BuildSpec != nil {
+						output = buildRun.Status.BuildSpec.Output.DeepCopy()
+					}
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(output).ToNot(BeNil())
+				Expect(output.ImageURL).To(Equal("quay.io/foobar/app:preview"))
+				Expect(output.Tags).To(Equal([]string{"{{.Revision.ShortSHA}}"}))
+			})
+		})
+
+		Context("cancelling a BuildRun", func() {
+			var updatedBuildRun *build.BuildRun
+
+			// getWithoutTaskRunStub returns the Build and the BuildRun, and no TaskRun
+			getWithoutTaskRunStub := func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+				switch object := object.(type) {
+				case *build.Build:
+					buildSample.DeepCopyInto(object)
+					return nil
+				case *build.BuildRun:
+					buildRunSample.DeepCopyInto(object)
+					return nil
+				}
+				return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+			}
+
+			BeforeEach(func() {
+				updatedBuildRun = nil
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				taskRunRequest = newReconcileRequest(taskRunName, ns)
+
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Spec.State = build.BuildRunStateCancelled
+				buildRunSample.Status.BuildSpec = &buildSample.Spec
+				buildRunSample.Status.LatestTaskRunRef = &taskRunName
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionUnknown, "Running")
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					updatedBuildRun = object.(*build.BuildRun)
+					return nil
+				})
+			})
+
+			It("cancels the running TaskRun and completes the BuildRun with the Cancelled reason", func() {
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.PatchCallCount()).To(Equal(1))
+				_, object, _, _ := client.PatchArgsForCall(0)
+				taskRun, ok := object.(*v1beta1.TaskRun)
+				Expect(ok).To(BeTrue())
+				Expect(taskRun.Name).To(Equal(taskRunName))
+				Expect(string(taskRun.Spec.Status)).To(Equal(v1beta1.TaskRunSpecStatusCancelled))
+
+				Expect(updatedBuildRun).ToNot(BeNil())
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonCancelled))
+				Expect(updatedBuildRun.Status.CompletionTime).ToNot(BeNil())
+			})
+
+			It("reconciles the cancellation from the BuildRun", func() {
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					if _, ok := object.(*v1beta1.TaskRun); ok && nn.Name == buildRunName {
+						return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+					}
+					return getClientStub(context, nn, object)
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PatchCallCount()).To(Equal(1))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonCancelled))
+			})
+
+			It("does not create a TaskRun for a BuildRun that is cancelled before it started", func() {
+				buildRunSample.Status.LatestTaskRunRef = nil
+				client.GetCalls(getWithoutTaskRunStub)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(client.PatchCallCount()).To(Equal(0))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonCancelled))
+			})
+
+			It("deletes the generated service account", func() {
+				buildRunSample.Spec.ServiceAccount = &build.ServiceAccount{Generate: true}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.DeleteCallCount()).To(Equal(1))
+				_, object, _ := client.DeleteArgsForCall(0)
+				serviceAccount, ok := object.(*corev1.ServiceAccount)
+				Expect(ok).To(BeTrue())
+				Expect(serviceAccount.Name).To(Equal(buildRunName + "-sa"))
+			})
+
+			It("does not change a BuildRun that already completed", func() {
+				now := metav1.Now()
+				buildRunSample.Status.CompletionTime = &now
+				client.GetCalls(getWithoutTaskRunStub)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PatchCallCount()).To(Equal(0))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("retrying a failed TaskRun", func() {
+			const retryTaskRunName = "foobar-buildrun-x7k2q"
+
+			var (
+				saName          string
+				updatedBuildRun *build.BuildRun
+			)
+
+			BeforeEach(func() {
+				saName = "foobar-sa"
+				updatedBuildRun = nil
+				taskRunRequest = newReconcileRequest(taskRunName, ns)
+
+				buildRunSample = ctl.BuildRunWithSA(buildRunName, buildName, saName)
+				buildRunSample.Status.BuildSpec = buildSample.Spec.DeepCopy()
+				buildRunSample.Status.BuildSpec.Retries = &build.Retries{Max: 2}
+				buildRunSample.Status.LatestTaskRunRef = &taskRunName
+				buildRunSample.Status.TaskRunRefs = []string{taskRunName}
+				buildRunSample.Status.Attempts = 1
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "Failed")
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *corev1.ServiceAccount:
+						ctl.DefaultServiceAccount(saName).DeepCopyInto(object)
+						return nil
+					case *build.ClusterBuildStrategy:
+						ctl.DefaultClusterBuildStrategy().DeepCopyInto(object)
+						return nil
+					}
+					return getClientStub(context, nn, object)
+				})
+				client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					if taskRun, ok := object.(*v1beta1.TaskRun); ok {
+						taskRun.Name = retryTaskRunName
+					}
+					return nil
+				})
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					updatedBuildRun = object.(*build.BuildRun)
+					return nil
+				})
+			})
+
+			It("creates a new TaskRun and records the attempt", func() {
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.CreateCallCount()).To(Equal(1))
+				_, object, _ := client.CreateArgsForCall(0)
+				taskRun, ok := object.(*v1beta1.TaskRun)
+				Expect(ok).To(BeTrue())
+				Expect(taskRun.Labels[build.LabelBuildRun]).To(Equal(buildRunName))
+
+				Expect(*updatedBuildRun.Status.LatestTaskRunRef).To(Equal(retryTaskRunName))
+				Expect(updatedBuildRun.Status.TaskRunRefs).To(Equal([]string{taskRunName, retryTaskRunName}))
+				Expect(updatedBuildRun.Status.Attempts).To(Equal(int32(2)))
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionUnknown))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonPending))
+				Expect(updatedBuildRun.Status.CompletionTime).To(BeNil())
+			})
+
+			It("waits for the backoff before it creates a new TaskRun", func() {
+				buildRunSample.Status.BuildSpec.Retries.Backoff = &metav1.Duration{Duration: time.Hour}
+				buildRunSample.Status.TaskRunRefs = []string{"foobar-buildrun-a1b2c", taskRunName}
+				buildRunSample.Status.Attempts = 2
+				taskRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+
+				result, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				// the backoff doubles for the second retry
+				Expect(result.RequeueAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonRetrying))
+			})
+
+			It("fails the BuildRun when the retries are used up", func() {
+				buildRunSample.Status.TaskRunRefs = []string{"foobar-buildrun-a1b2c", "foobar-buildrun-d3e4f", taskRunName}
+				buildRunSample.Status.Attempts = 3
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonFailed))
+				Expect(updatedBuildRun.Status.Attempts).To(Equal(int32(3)))
+			})
+
+			It("does not retry a TaskRun that timed out", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "TaskRunTimeout")
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonTimeout))
+			})
+
+			It("fails the BuildRun without a retry when the image violates the vulnerability policy", func() {
+				taskRunSample.Status.Steps = []v1beta1.StepState{{
+					Name: "scan-image",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 3},
+					},
+				}}
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-vulnerabilities", Value: `{"critical":1,"high":2,"medium":5,"low":9,"unknown":0}`},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.CreateCallCount()).To(Equal(0))
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonVulnerabilityPolicyViolation))
+				Expect(condition.Message).To(ContainSubstring("1 critical, 2 high, 5 medium, 9 low, 0 unknown"))
+				Expect(updatedBuildRun.Status.Vulnerabilities).To(Equal(&build.VulnerabilitySummary{Critical: 1, High: 2, Medium: 5, Low: 9}))
+			})
+
+			It("retries a TaskRun whose scan step failed with an error", func() {
+				taskRunSample.Status.Steps = []v1beta1.StepState{{
+					Name: "scan-image",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+					},
+				}}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("ignores the events of the TaskRuns of previous attempts", func() {
+				latestTaskRunName := retryTaskRunName
+				buildRunSample.Status.TaskRunRefs = []string{taskRunName, retryTaskRunName}
+				buildRunSample.Status.LatestTaskRunRef = &latestTaskRunName
+				buildRunSample.Status.Attempts = 2
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("enforcing the concurrency policy", func() {
+			var (
+				saName          string
+				updatedBuildRun *build.BuildRun
+				otherBuildRuns  []build.BuildRun
+			)
+
+			// newOtherBuildRun returns another BuildRun of the Build that was created the given time
+			// before the reconciled BuildRun, and that runs if it has a TaskRun
+			newOtherBuildRun := func(name string, createdBefore time.Duration, withTaskRun bool) build.BuildRun {
+				other := ctl.DefaultBuildRun(name, buildName)
+				other.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(-createdBefore))
+				if withTaskRun {
+					other.Status.LatestTaskRunRef = &taskRunName
+				}
+				return *other
+			}
+
+			BeforeEach(func() {
+				saName = "foobar-sa"
+				updatedBuildRun = nil
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+
+				buildRunSample = ctl.BuildRunWithSA(buildRunName, buildName, saName)
+				buildRunSample.CreationTimestamp = metav1.Now()
+				otherBuildRuns = nil
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+				client.ListCalls(func(_ context.Context, list runtime.Object, _ ...crc.ListOption) error {
+					if list, ok := list.(*build.BuildRunList); ok {
+						list.Items = append([]build.BuildRun{*buildRunSample}, otherBuildRuns...)
+					}
+					return nil
+				})
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					updatedBuildRun = object.(*build.BuildRun)
+					return nil
+				})
+			})
+
+			It("does not list the other BuildRuns if the Build allows concurrent BuildRuns", func() {
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", time.Minute, true)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				for i := 0; i < client.ListCallCount(); i++ {
+					_, list, _ := client.ListArgsForCall(i)
+					Expect(list).ToNot(BeAssignableToTypeOf(&build.BuildRunList{}))
+				}
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("fails the BuildRun if another one is running and the policy is Forbid", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyForbid
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", time.Minute, true)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonConcurrencyForbidden))
+				Expect(condition.Message).To(ContainSubstring("foobar-buildrun-1"))
+				Expect(updatedBuildRun.Status.CompletionTime).ToNot(BeNil())
+			})
+
+			It("ignores the completed and cancelled BuildRuns of the Build", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyForbid
+				completed := newOtherBuildRun("foobar-buildrun-1", time.Minute, true)
+				completed.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				cancelled := newOtherBuildRun("foobar-buildrun-2", time.Minute, true)
+				cancelled.Spec.State = build.BuildRunStateCancelled
+				otherBuildRuns = []build.BuildRun{completed, cancelled}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("cancels the running BuildRuns and creates the TaskRun if the policy is Replace", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyReplace
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", time.Minute, true)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(client.PatchCallCount()).To(Equal(1))
+				_, object, _, _ := client.PatchArgsForCall(0)
+				replaced, ok := object.(*build.BuildRun)
+				Expect(ok).To(BeTrue())
+				Expect(replaced.Name).To(Equal("foobar-buildrun-1"))
+				Expect(replaced.Spec.State).To(Equal(build.BuildRunStateCancelled))
+
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("does not start a BuildRun that a newer one replaces", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyReplace
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", -time.Second, false)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PatchCallCount()).To(Equal(0))
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonReplaced))
+			})
+
+			It("keeps the BuildRun pending while an older one did not complete if the policy is Queue", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyQueue
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", time.Minute, false)}
+
+				result, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionUnknown))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonPending))
+				Expect(condition.Message).To(ContainSubstring("foobar-buildrun-1"))
+				Expect(updatedBuildRun.Status.CompletionTime).To(BeNil())
+			})
+
+			It("starts a queued BuildRun before the newer ones", func() {
+				buildSample.Spec.ConcurrencyPolicy = build.ConcurrencyPolicyQueue
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun("foobar-buildrun-1", -time.Minute, false)}
+
+				result, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("admitting BuildRuns by ClusterBuildRunQuotas", func() {
+			var (
+				saName          string
+				updatedBuildRun *build.BuildRun
+				quotas          []build.ClusterBuildRunQuota
+				otherBuildRuns  []build.BuildRun
+			)
+
+			int32Ptr := func(i int32) *int32 { return &i }
+
+			// newOtherBuildRun returns a BuildRun in the namespace that was created the given time before
+			// the reconciled BuildRun, it runs or it is queued by the quotas
+			newOtherBuildRun := func(namespace string, name string, createdBefore time.Duration, running bool) build.BuildRun {
+				other := ctl.DefaultBuildRun(name, buildName)
+				other.Namespace = namespace
+				other.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(-createdBefore))
+				if running {
+					other.Status.LatestTaskRunRef = &taskRunName
+				} else {
+					other.Status.QueuePosition = int32Ptr(1)
+				}
+				return *other
+			}
+
+			BeforeEach(func() {
+				saName = "foobar-sa"
+				updatedBuildRun = nil
+				quotas, otherBuildRuns = nil, nil
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+
+				buildRunSample = ctl.BuildRunWithSA(buildRunName, buildName, saName)
+				buildRunSample.Namespace = ns
+				buildRunSample.CreationTimestamp = metav1.Now()
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+				client.ListCalls(func(_ context.Context, list runtime.Object, _ ...crc.ListOption) error {
+					switch list := list.(type) {
+					case *build.ClusterBuildRunQuotaList:
+						list.Items = quotas
+					case *build.BuildRunList:
+						list.Items = append([]build.BuildRun{*buildRunSample}, otherBuildRuns...)
+					}
+					return nil
+				})
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					updatedBuildRun = object.(*build.BuildRun)
+					return nil
+				})
+			})
+
+			It("creates the TaskRun if there is no quota", func() {
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun(ns, "foobar-buildrun-1", time.Minute, true)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+				Expect(updatedBuildRun.Status.QueuePosition).To(BeNil())
+			})
+
+			It("queues the BuildRun if its namespace reached the limit", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(2)}}}
+				otherBuildRuns = []build.BuildRun{
+					newOtherBuildRun(ns, "foobar-buildrun-1", time.Minute, true),
+					newOtherBuildRun(ns, "foobar-buildrun-2", time.Minute, true),
+				}
+
+				result, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				Expect(*updatedBuildRun.Status.QueuePosition).To(Equal(int32(1)))
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Status).To(Equal(corev1.ConditionUnknown))
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonPending))
+				Expect(updatedBuildRun.Status.CompletionTime).To(BeNil())
+			})
+
+			It("does not count the BuildRuns of other namespaces against the limit of a namespace", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(2)}}}
+				otherBuildRuns = []build.BuildRun{
+					newOtherBuildRun("team-a", "foobar-buildrun-1", time.Minute, true),
+					newOtherBuildRun("team-a", "foobar-buildrun-2", time.Minute, true),
+				}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("applies the strictest limit of all quotas", func() {
+				quotas = []build.ClusterBuildRunQuota{
+					{Spec: build.ClusterBuildRunQuotaSpec{MaxRunning: int32Ptr(10)}},
+					{Spec: build.ClusterBuildRunQuotaSpec{MaxRunning: int32Ptr(2)}},
+				}
+				otherBuildRuns = []build.BuildRun{
+					newOtherBuildRun("team-a", "foobar-buildrun-1", time.Minute, true),
+					newOtherBuildRun("team-b", "foobar-buildrun-2", time.Minute, true),
+				}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(*updatedBuildRun.Status.QueuePosition).To(Equal(int32(1)))
+			})
+
+			It("admits the BuildRuns of a namespace with fewer running BuildRuns first", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunning: int32Ptr(3)}}}
+				otherBuildRuns = []build.BuildRun{
+					newOtherBuildRun("team-a", "foobar-buildrun-1", time.Hour, true),
+					newOtherBuildRun("team-a", "foobar-buildrun-2", time.Hour, true),
+					newOtherBuildRun("team-a", "foobar-buildrun-3", time.Minute, false),
+				}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("reports the position behind the BuildRuns that are admitted before", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunning: int32Ptr(1)}}}
+				otherBuildRuns = []build.BuildRun{
+					newOtherBuildRun("team-a", "foobar-buildrun-1", time.Hour, true),
+					newOtherBuildRun("team-b", "foobar-buildrun-2", time.Minute, false),
+					newOtherBuildRun(ns, "foobar-buildrun-3", time.Minute, false),
+				}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				// the BuildRuns of the namespaces without running BuildRuns are in one round, the
+				// older BuildRun of the same namespace comes first
+				Expect(*updatedBuildRun.Status.QueuePosition).To(Equal(int32(3)))
+			})
+
+			It("admits a BuildRun with a higher priority before the older ones of its namespace", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(1)}}}
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun(ns, "foobar-buildrun-1", time.Minute, false)}
+				buildRunSample.Spec.Priority = int32Ptr(10)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+			})
+
+			It("keeps the older BuildRun first if the priorities are equal", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(1)}}}
+				otherBuildRuns = []build.BuildRun{newOtherBuildRun(ns, "foobar-buildrun-1", time.Minute, false)}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(*updatedBuildRun.Status.QueuePosition).To(Equal(int32(1)))
+			})
+
+			It("counts an admitted BuildRun without TaskRun as running", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(1)}}}
+				admitted := ctl.DefaultBuildRun("foobar-buildrun-1", buildName)
+				admitted.Namespace = ns
+				admitted.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(-time.Minute))
+				admitted.Status.BuildSpec = &buildSample.Spec
+				otherBuildRuns = []build.BuildRun{*admitted}
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(*updatedBuildRun.Status.QueuePosition).To(Equal(int32(1)))
+			})
+
+			It("clears the queue position when the BuildRun is admitted", func() {
+				quotas = []build.ClusterBuildRunQuota{{Spec: build.ClusterBuildRunQuotaSpec{MaxRunningPerNamespace: int32Ptr(1)}}}
+				buildRunSample.Status.QueuePosition = int32Ptr(2)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+				Expect(updatedBuildRun.Status.QueuePosition).To(BeNil())
+			})
+		})
+
+		Context("building for platforms", func() {
+			const (
+				digestAMD64 = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+				digestARM64 = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+			)
+
+			var (
+				taskRuns        []v1beta1.TaskRun
+				updatedBuildRun *build.BuildRun
+			)
+
+			newPlatformTaskRun := func(name string, platform string, status corev1.ConditionStatus, reason string, digest string) v1beta1.TaskRun {
+				taskRun := ctl.DefaultTaskRunWithStatus(name, buildRunName, ns, status, reason)
+				taskRun.Labels[build.LabelBuildRunPlatform] = platform
+				if digest != "" {
+					taskRun.Status.TaskRunResults = []v1beta1.TaskRunResult{{Name: "shp-image-digest", Value: digest}}
+				}
+				return *taskRun
+			}
+
+			BeforeEach(func() {
+				taskRuns, updatedBuildRun = nil, nil
+
+				buildSample.Spec.Output.ImageURL = "quay.io/foobar/app:latest"
+				buildSample.Spec.Platforms = []string{"linux/amd64", "linux/arm64"}
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Namespace = ns
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					if buildRun, ok := object.(*build.BuildRun); ok {
+						updatedBuildRun = buildRun.DeepCopy()
+					}
+					return nil
+				})
+			})
+
+			It("creates a TaskRun for every platform on a node of the platform", func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount("pipeline"),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				var created []*v1beta1.TaskRun
+				client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					if taskRun, ok := object.(*v1beta1.TaskRun); ok {
+						taskRun.Name = fmt.Sprintf("%s%d", taskRun.GenerateName, len(created))
+						created = append(created, taskRun.DeepCopy())
+					}
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(HaveLen(2))
+
+				arm64 := created[1]
+				Expect(arm64.Labels[build.LabelBuildRunPlatform]).To(Equal("linux-arm64"))
+				Expect(arm64.Spec.PodTemplate.NodeSelector).To(Equal(map[string]string{
+					"kubernetes.io/os":   "linux",
+					"kubernetes.io/arch": "arm64",
+				}))
+				for _, param := range arm64.Spec.Params {
+					if param.Name == "OUTPUT_IMAGE" {
+						Expect(param.Value.StringVal).To(Equal("quay.io/foobar/app:latest-linux-arm64"))
+					}
+				}
+
+				Expect(*updatedBuildRun.Status.LatestTaskRunRef).To(Equal(created[0].Name))
+				Expect(updatedBuildRun.Status.Platforms).To(Equal([]build.PlatformResult{
+					{Platform: "linux/amd64", TaskRunRef: created[0].Name, Image: "quay.io/foobar/app:latest-linux-amd64"},
+					{Platform: "linux/arm64", TaskRunRef: created[1].Name, Image: "quay.io/foobar/app:latest-linux-arm64"},
+				}))
+			})
+
+			It("cancels the TaskRuns of the earlier platforms if the TaskRun of a platform cannot be created", func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount("pipeline"),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				var created []*v1beta1.TaskRun
+				client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					if taskRun, ok := object.(*v1beta1.TaskRun); ok {
+						if len(created) > 0 {
+							return fmt.Errorf("quota exceeded")
+						}
+						taskRun.Name = taskRun.GenerateName + "0"
+						created = append(created, taskRun.DeepCopy())
+					}
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).To(HaveOccurred())
+				Expect(updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded).Reason).To(Equal(build.BuildRunReasonTaskRunCreationFailed))
+
+				Expect(client.PatchCallCount()).To(Equal(1))
+				_, object, _, _ := client.PatchArgsForCall(0)
+				Expect(object.(*v1beta1.TaskRun).Name).To(Equal(created[0].Name))
+				Expect(string(object.(*v1beta1.TaskRun).Spec.Status)).To(Equal(v1beta1.TaskRunSpecStatusCancelled))
+			})
+
+			It("fails the BuildRun without TaskRuns if it has retries", func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				buildRunSample.Spec.Retries = &build.Retries{Max: 2}
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount("pipeline"),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonTaskRunGenerationFailed))
+				Expect(condition.Message).To(ContainSubstring("retries are not supported"))
+			})
+
+			It("fails the BuildRun without TaskRuns if its output is signed", func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				buildRunSample.Spec.Output = &build.Image{
+					ImageURL: "quay.io/foobar/app:latest",
+					Signing:  &build.ImageSigning{SecretRef: corev1.LocalObjectReference{Name: "signing-key"}},
+				}
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount("pipeline"),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonTaskRunGenerationFailed))
+				Expect(condition.Message).To(ContainSubstring("signing is not supported"))
+			})
+
+			It("fails the BuildRun without TaskRuns if it generates an SBOM", func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+				buildRunSample.Spec.Output = &build.Image{
+					ImageURL: "quay.io/foobar/app:latest",
+					SBOM:     &build.ImageSBOM{},
+				}
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount("pipeline"),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+
+				condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+				Expect(condition.Reason).To(Equal(build.BuildRunReasonTaskRunGenerationFailed))
+				Expect(condition.Message).To(ContainSubstring("SBOMs are not supported"))
+			})
+
+			Context("from the TaskRuns of the platforms", func() {
+				BeforeEach(func() {
+					taskRunRequest = newReconcileRequest("foobar-buildrun-amd64", ns)
+					buildRunSample.Status.BuildSpec = &buildSample.Spec
+
+					client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+						switch object := object.(type) {
+						case *build.BuildRun:
+							buildRunSample.DeepCopyInto(object)
+							return nil
+						case *v1beta1.TaskRun:
+							for _, taskRun := range taskRuns {
+								if taskRun.Name == nn.Name {
+									taskRun.DeepCopyInto(object)
+									return nil
+								}
+							}
+						}
+						return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+					})
+					client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+						if list, ok := object.(*v1beta1.TaskRunList); ok {
+							list.Items = taskRuns
+						}
+						return nil
+					})
+				})
+
+				It("keeps the BuildRun running while a platform did not complete", func() {
+					taskRuns = []v1beta1.TaskRun{
+						newPlatformTaskRun("foobar-buildrun-amd64", "linux-amd64", corev1.ConditionTrue, "Succeeded", digestAMD64),
+						newPlatformTaskRun("foobar-buildrun-arm64", "linux-arm64", corev1.ConditionUnknown, "Running", ""),
+					}
+
+					_, err := reconciler.Reconcile(taskRunRequest)
+					Expect(err).ToNot(HaveOccurred())
+
+					condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+					Expect(condition.Status).To(Equal(corev1.ConditionUnknown))
+					Expect(condition.Reason).To(Equal(build.BuildRunReasonRunning))
+					Expect(updatedBuildRun.Status.CompletionTime).To(BeNil())
+					Expect(updatedBuildRun.Status.Platforms[0].Digest).To(Equal(digestAMD64))
+					Expect(updatedBuildRun.Status.Platforms[1].TaskRunRef).To(Equal("foobar-buildrun-arm64"))
+				})
+
+				It("fails the BuildRun and cancels the other TaskRuns when a platform fails", func() {
+					taskRuns = []v1beta1.TaskRun{
+						newPlatformTaskRun("foobar-buildrun-amd64", "linux-amd64", corev1.ConditionFalse, "Failed", ""),
+						newPlatformTaskRun("foobar-buildrun-arm64", "linux-arm64", corev1.ConditionUnknown, "Running", ""),
+					}
+
+					_, err := reconciler.Reconcile(taskRunRequest)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(client.PatchCallCount()).To(Equal(1))
+					_, patched, _, _ := client.PatchArgsForCall(0)
+					Expect(patched.(*v1beta1.TaskRun).Name).To(Equal("foobar-buildrun-arm64"))
+					Expect(string(patched.(*v1beta1.TaskRun).Spec.Status)).To(Equal(v1beta1.TaskRunSpecStatusCancelled))
+
+					condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(build.BuildRunReasonFailed))
+					Expect(condition.Message).To(ContainSubstring("the TaskRun foobar-buildrun-amd64 of the platform linux/amd64 failed"))
+					Expect(updatedBuildRun.Status.CompletionTime).ToNot(BeNil())
+				})
+
+				Context("when all platforms succeeded", func() {
+					var (
+						server   *httptest.Server
+						host     string
+						requests []string
+					)
+
+					BeforeEach(func() {
+						requests = nil
+						server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							requests = append(requests, r.Method+" "+r.URL.Path)
+							if r.Method == http.MethodPut {
+								Expect(r.Header.Get("Content-Type")).To(Equal("application/vnd.oci.image.index.v1+json"))
+								w.WriteHeader(http.StatusCreated)
+								return
+							}
+							w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+							w.Write([]byte(`{"schemaVersion": 2}`))
+						}))
+						host = server.Listener.Addr().String()
+						buildSample.Spec.Output.ImageURL = host + "/foobar/app:latest"
+
+						taskRuns = []v1beta1.TaskRun{
+							newPlatformTaskRun("foobar-buildrun-amd64", "linux-amd64", corev1.ConditionTrue, "Succeeded", digestAMD64),
+							newPlatformTaskRun("foobar-buildrun-arm64", "linux-arm64", corev1.ConditionTrue, "Succeeded", digestARM64),
+						}
+					})
+
+					AfterEach(func() {
+						server.Close()
+					})
+
+					It("pushes the image index of the platforms and records all digests", func() {
+						_, err := reconciler.Reconcile(taskRunRequest)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(requests).To(Equal([]string{
+							"GET /v2/foobar/app/manifests/" + digestAMD64,
+							"GET /v2/foobar/app/manifests/" + digestARM64,
+							"PUT /v2/foobar/app/manifests/latest",
+						}))
+
+						condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+						Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+						Expect(updatedBuildRun.Status.Output.Image).To(Equal(host + "/foobar/app:latest"))
+						Expect(updatedBuildRun.Status.Output.Digest).To(HavePrefix("sha256:"))
+						Expect(updatedBuildRun.Status.Platforms).To(Equal([]build.PlatformResult{
+							{Platform: "linux/amd64", TaskRunRef: "foobar-buildrun-amd64", Image: host + "/foobar/app:latest-linux-amd64", Digest: digestAMD64},
+							{Platform: "linux/arm64", TaskRunRef: "foobar-buildrun-arm64", Image: host + "/foobar/app:latest-linux-arm64", Digest: digestARM64},
+						}))
+						Expect(updatedBuildRun.Status.CompletionTime).ToNot(BeNil())
+					})
+
+					It("fails the BuildRun with the ImageIndexFailed reason if the registry rejects the index", func() {
+						server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusForbidden)
+						})
+
+						_, err := reconciler.Reconcile(taskRunRequest)
+						Expect(err).ToNot(HaveOccurred())
+
+						condition := updatedBuildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+						Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+						Expect(condition.Reason).To(Equal(build.BuildRunReasonImageIndexFailed))
+						Expect(updatedBuildRun.Status.Output).To(BeNil())
+					})
+				})
+			})
 		})
 	})
 })
@@ -0,0 +1,126 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/commitstatus"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getCommitStatusState returns the commit status state for the status and reason of the
+// Succeeded condition of a BuildRun, and false if the condition is not reported
+func getCommitStatusState(status corev1.ConditionStatus, reason string) (commitstatus.State, bool) {
+	switch {
+	case status == corev1.ConditionTrue:
+		return commitstatus.StateSucceeded, true
+	case status == corev1.ConditionFalse:
+		return commitstatus.StateFailed, true
+	case reason == buildv1alpha1.BuildRunReasonRunning:
+		return commitstatus.StateRunning, true
+	default:
+		return "", false
+	}
+}
+
+// reportCommitStatus reports the state of the BuildRun as commit status to the git provider of
+// the source, if its flavor supports it. The reason of the Succeeded condition is part of the
+// description. All reports of a BuildRun go to the commit of its first report. A commit
+// status is informational, errors are therefore logged and do not affect the BuildRun.
+func (r *ReconcileBuildRun) reportCommitStatus(ctx context.Context, buildRun *buildv1alpha1.BuildRun, state commitstatus.State, reason string) {
+	buildSpec := buildRun.Status.BuildSpec
+	if buildSpec == nil {
+		return
+	}
+	source := buildSpec.Source
+
+	// the generic flavor does not support commit statuses, there is no need to read the credentials
+	if flavor := strings.ToLower(source.Flavor); flavor == "" || flavor == commitstatus.FlavorGeneric {
+		return
+	}
+
+	credentials, err := r.getSourceCredentials(ctx, buildRun.Namespace, source)
+	if err != nil {
+		ctxlog.Error(ctx, err, "failed to retrieve the source credentials for the commit status", namespace, buildRun.Namespace, name, buildRun.Name)
+		return
+	}
+
+	reporter, err := commitstatus.NewReporter(source.Flavor, source.URL, credentials, nil)
+	if err != nil {
+		ctxlog.Error(ctx, err, "failed to report the commit status", namespace, buildRun.Namespace, name, buildRun.Name)
+		return
+	}
+
+	// the commit is resolved once and stored, a branch that moves during the BuildRun must not
+	// leave the status of the first commit pending
+	sha := buildRun.Status.CommitStatusSha
+	if sha == "" {
+		if sha, err = getCommitSha(ctx, buildRun, reporter); err != nil {
+			ctxlog.Error(ctx, err, "failed to resolve the revision for the commit status", namespace, buildRun.Namespace, name, buildRun.Name)
+			return
+		}
+
+		buildRun.Status.CommitStatusSha = sha
+		if err := r.client.Status().Update(ctx, buildRun); err != nil {
+			ctxlog.Error(ctx, err, "failed to store the commit of the commit status", namespace, buildRun.Namespace, name, buildRun.Name)
+			return
+		}
+	}
+
+	status := commitstatus.Status{
+		State:       state,
+		Context:     fmt.Sprintf("shipwright/%s", buildRun.Spec.BuildRef.Name),
+		Description: fmt.Sprintf("BuildRun %s: %s", buildRun.Name, reason),
+	}
+
+	ctxlog.Info(ctx, "reporting commit status", namespace, buildRun.Namespace, name, buildRun.Name, "commit", sha, "state", state)
+	if err := reporter.Report(ctx, sha, status); err != nil {
+		ctxlog.Error(ctx, err, "failed to report the commit status", namespace, buildRun.Namespace, name, buildRun.Name)
+	}
+}
+
+// getSourceCredentials reads the username and password of the basic-auth secret of the source,
+// the password is used as access token of the git provider
+func (r *ReconcileBuildRun) getSourceCredentials(ctx context.Context, ns string, source buildv1alpha1.GitSource) (commitstatus.Credentials, error) {
+	credentials := commitstatus.Credentials{}
+	if source.SecretRef == nil {
+		return credentials, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: source.SecretRef.Name, Namespace: ns}, secret); err != nil {
+		return credentials, err
+	}
+
+	credentials.Username = string(secret.Data[corev1.BasicAuthUsernameKey])
+	credentials.Password = string(secret.Data[corev1.BasicAuthPasswordKey])
+	return credentials, nil
+}
+
+// getCommitSha returns the commit that the BuildRun checked out, or resolves the requested
+// revision through the git provider if the source step did not yet report the commit
+func getCommitSha(ctx context.Context, buildRun *buildv1alpha1.BuildRun, reporter commitstatus.Reporter) (string, error) {
+	for _, source := range buildRun.Status.Sources {
+		if source.Name == defaultSourceName && source.Git != nil && source.Git.CommitSha != "" {
+			return source.Git.CommitSha, nil
+		}
+	}
+
+	revision := defaultSourceRevision
+	if buildRun.Status.BuildSpec.Source.Revision != nil {
+		revision = *buildRun.Status.BuildSpec.Source.Revision
+	}
+	if commitstatus.IsCommitSha(revision) {
+		return revision, nil
+	}
+
+	return reporter.ResolveRevision(ctx, revision)
+}
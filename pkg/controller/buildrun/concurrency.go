@@ -0,0 +1,133 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// queueInterval is the time after which a queued BuildRun checks again whether it can start
+const queueInterval = 10 * time.Second
+
+// enforceConcurrencyPolicy applies the concurrency policy of the Build before the TaskRun of the
+// BuildRun is created. It returns true if the TaskRun can be created, otherwise the BuildRun
+// waits with the returned result, or it is completed.
+func (r *ReconcileBuildRun) enforceConcurrencyPolicy(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (bool, reconcile.Result, error) {
+	policy := build.Spec.ConcurrencyPolicy
+	if policy == "" || policy == buildv1alpha1.ConcurrencyPolicyAllow {
+		return true, reconcile.Result{}, nil
+	}
+
+	older, newer, err := r.getConcurrentBuildRuns(ctx, build, buildRun)
+	if err != nil {
+		return false, reconcile.Result{}, err
+	}
+
+	switch policy {
+	case buildv1alpha1.ConcurrencyPolicyForbid:
+		if len(older) == 0 {
+			return true, reconcile.Result{}, nil
+		}
+		message := fmt.Sprintf("the BuildRuns %s of the Build %s are running, its concurrency policy forbids another one", buildRunNames(older), build.Name)
+		ctxlog.Info(ctx, "BuildRun is forbidden by the concurrency policy", namespace, buildRun.Namespace, name, buildRun.Name, "running", buildRunNames(older))
+		return false, reconcile.Result{}, r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonConcurrencyForbidden, message)
+
+	case buildv1alpha1.ConcurrencyPolicyReplace:
+		// a newer BuildRun replaces this one, this happens if the BuildRuns were created
+		// shortly after each other
+		if len(newer) > 0 {
+			message := fmt.Sprintf("the BuildRun is replaced by the newer BuildRuns %s", buildRunNames(newer))
+			ctxlog.Info(ctx, "BuildRun is replaced by a newer one", namespace, buildRun.Namespace, name, buildRun.Name, "newer", buildRunNames(newer))
+			return false, reconcile.Result{}, r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonReplaced, message)
+		}
+
+		// the older BuildRuns are cancelled, their reconciliation stops their TaskRuns
+		for _, other := range older {
+			patch := client.MergeFrom(other.DeepCopy())
+			other.Spec.State = buildv1alpha1.BuildRunStateCancelled
+
+			ctxlog.Info(ctx, "cancelling BuildRun that is replaced", namespace, other.Namespace, name, other.Name, "replacedBy", buildRun.Name)
+			if err := r.client.Patch(ctx, other, patch); err != nil && !apierrors.IsNotFound(err) {
+				return false, reconcile.Result{}, err
+			}
+		}
+		return true, reconcile.Result{}, nil
+
+	case buildv1alpha1.ConcurrencyPolicyQueue:
+		if len(older) == 0 {
+			return true, reconcile.Result{}, nil
+		}
+
+		message := fmt.Sprintf("the BuildRun is queued after the BuildRuns %s", buildRunNames(older))
+		if condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded); condition == nil || condition.Message != message {
+			buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, message)
+			ctxlog.Info(ctx, "BuildRun is queued", namespace, buildRun.Namespace, name, buildRun.Name, "queuedAfter", buildRunNames(older))
+			if err := r.client.Status().Update(ctx, buildRun); err != nil {
+				return false, reconcile.Result{}, err
+			}
+		}
+		return false, reconcile.Result{RequeueAfter: queueInterval}, nil
+
+	default:
+		return true, reconcile.Result{}, nil
+	}
+}
+
+// getConcurrentBuildRuns returns the other BuildRuns of the Build that did not complete and are not
+// cancelled, split into the ones that run or were created before the BuildRun, and the newer ones
+func (r *ReconcileBuildRun) getConcurrentBuildRuns(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) ([]*buildv1alpha1.BuildRun, []*buildv1alpha1.BuildRun, error) {
+	buildRunList := &buildv1alpha1.BuildRunList{}
+	if err := r.client.List(ctx, buildRunList, &client.ListOptions{
+		Namespace:     buildRun.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{buildv1alpha1.LabelBuild: build.Name}),
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	var older, newer []*buildv1alpha1.BuildRun
+	for i := range buildRunList.Items {
+		other := &buildRunList.Items[i]
+		if other.Name == buildRun.Name || other.Status.CompletionTime != nil || other.Spec.IsCancelled() || other.DeletionTimestamp != nil {
+			continue
+		}
+
+		// a BuildRun that already has a TaskRun runs, even if it was created later
+		if other.Status.LatestTaskRunRef != nil || isCreatedBefore(other, buildRun) {
+			older = append(older, other)
+		} else {
+			newer = append(newer, other)
+		}
+	}
+	return older, newer, nil
+}
+
+// isCreatedBefore orders BuildRuns by their creation, and by their name if they were created in the same second
+func isCreatedBefore(a *buildv1alpha1.BuildRun, b *buildv1alpha1.BuildRun) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// buildRunNames returns the comma separated names of the BuildRuns
+func buildRunNames(buildRuns []*buildv1alpha1.BuildRun) string {
+	names := make([]string, 0, len(buildRuns))
+	for _, buildRun := range buildRuns {
+		names = append(names, buildRun.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -7,12 +7,11 @@ package buildrun
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/controller/utils"
-	taskv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/shipwright-io/build/pkg/placeholder"
 	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,61 +20,128 @@ import (
 const (
 	defaultServiceAccountName  = "default"
 	pipelineServiceAccountName = "pipeline"
-	inputSourceResourceName    = "source"
-	inputGitSourceURL          = "url"
-	inputGitSourceRevision     = "revision"
+	inputParamSourceURL        = "SOURCE_URL"
+	inputParamSourceRevision   = "SOURCE_REVISION"
+	inputParamOutputImage      = "OUTPUT_IMAGE"
 	inputParamBuilderImage     = "BUILDER_IMAGE"
 	inputParamDockerfile       = "DOCKERFILE"
 	inputParamContextDir       = "CONTEXT_DIR"
-	outputImageResourceName    = "image"
-	outputImageResourceURL     = "url"
+	inputParamPrefix           = "PARAM_"
+	resultImageDigest          = "shp-image-digest"
+	resultImageSize            = "shp-image-size"
 )
 
-// getStringTransformations gets us MANDATORY replacements using
-// a poor man's templating mechanism - TODO: Use golang templating
-func getStringTransformations(fullText string) string {
+// getPlaceholderResolver returns the resolver that maps the build placeholders, which are used in
+// the strategy steps, to the Task inputs and outputs
+func getPlaceholderResolver(parameters []buildv1alpha1.ParameterDefinition) *placeholder.Resolver {
+	values := map[string]string{
+		placeholder.OutputImage:      fmt.Sprintf("$(inputs.params.%s)", inputParamOutputImage),
+		placeholder.BuilderImage:     fmt.Sprintf("$(inputs.params.%s)", inputParamBuilderImage),
+		placeholder.Dockerfile:       fmt.Sprintf("$(inputs.params.%s)", inputParamDockerfile),
+		placeholder.SourceContextDir: fmt.Sprintf("$(inputs.params.%s)", inputParamContextDir),
 
-	stringTransformations := map[string]string{
-		"$(build.output.image)":      "$(outputs.resources.image.url)",
-		"$(build.builder.image)":     fmt.Sprintf("$(inputs.params.%s)", inputParamBuilderImage),
-		"$(build.dockerfile)":        fmt.Sprintf("$(inputs.params.%s)", inputParamDockerfile),
-		"$(build.source.contextDir)": fmt.Sprintf("$(inputs.params.%s)", inputParamContextDir),
+		placeholder.ImageDigestResultPath: fmt.Sprintf("$(results.%s.path)", resultImageDigest),
+		placeholder.ImageSizeResultPath:   fmt.Sprintf("$(results.%s.path)", resultImageSize),
 	}
 
-	// Run the text through all possible replacements
-	for k, v := range stringTransformations {
-		fullText = strings.ReplaceAll(fullText, k, v)
+	// Every parameter of the strategy is mapped to its own input parameter of the Task
+	for _, parameter := range parameters {
+		values[placeholder.Parameter(parameter.Name)] = fmt.Sprintf("$(inputs.params.%s)", taskParamName(parameter.Name))
+	}
+
+	return placeholder.NewResolver(placeholder.Build, values)
+}
+
+// taskParamName returns the name of the Task input parameter that carries the
+// value of the strategy parameter with the provided name
+func taskParamName(parameterName string) string {
+	return inputParamPrefix + parameterName
+}
+
+// outputImageScript is the start of the shell scripts of the steps that work on the pushed output
+// image. It sets IMAGE to the output image with the digest that the build strategy reported, so
+// that the steps work on what was pushed even if the tag moves meanwhile, or to the output image
+// with its tag if the strategy reported no digest.
+var outputImageScript = fmt.Sprintf(`IMAGE="$(inputs.params.%s)"
+DIGEST="$(cat "$(results.%s.path)" 2>/dev/null || true)"
+if [ -n "$DIGEST" ]; then
+  REPOSITORY="${IMAGE%%@*}"
+  case "${REPOSITORY##*/}" in *:*) REPOSITORY="${REPOSITORY%%:*}" ;; esac
+  IMAGE="${REPOSITORY}@${DIGEST}"
+fi
+`, inputParamOutputImage, resultImageDigest)
+
+// getTaskParamSpec returns the Task parameter specification for a strategy parameter
+func getTaskParamSpec(parameter buildv1alpha1.ParameterDefinition) v1beta1.ParamSpec {
+	paramSpec := v1beta1.ParamSpec{
+		Description: parameter.Description,
+		Name:        taskParamName(parameter.Name),
+		Type:        v1beta1.ParamTypeString,
+	}
+
+	switch parameter.GetType() {
+	case buildv1alpha1.ParameterTypeString:
+		if parameter.Default != nil {
+			paramSpec.Default = &v1beta1.ArrayOrString{
+				Type:      v1beta1.ParamTypeString,
+				StringVal: *parameter.Default,
+			}
+		}
+	case buildv1alpha1.ParameterTypeArray:
+		paramSpec.Type = v1beta1.ParamTypeArray
+		if parameter.Defaults != nil {
+			paramSpec.Default = &v1beta1.ArrayOrString{
+				Type:     v1beta1.ParamTypeArray,
+				ArrayVal: *parameter.Defaults,
+			}
+		}
+	}
+	return paramSpec
+}
+
+// getTaskParamValue returns the value of a Build parameter as Task parameter value
+func getTaskParamValue(parameter buildv1alpha1.Parameter) v1beta1.ArrayOrString {
+	if parameter.Values != nil {
+		return v1beta1.ArrayOrString{
+			Type:     v1beta1.ParamTypeArray,
+			ArrayVal: parameter.Values,
+		}
+	}
+	return v1beta1.ArrayOrString{
+		Type:      v1beta1.ParamTypeString,
+		StringVal: parameter.Value,
 	}
-	return fullText
 }
 
 func GenerateTaskSpec(
 	cfg *config.Config,
 	build *buildv1alpha1.Build,
 	buildRun *buildv1alpha1.BuildRun,
-	buildSteps []buildv1alpha1.BuildStep,
+	strategy buildv1alpha1.BuilderStrategy,
 ) (*v1beta1.TaskSpec, error) {
 
+	buildSteps := strategy.GetBuildSteps()
+	parameters := strategy.GetParameters()
+
 	generatedTaskSpec := v1beta1.TaskSpec{
-		Resources: &v1beta1.TaskResources{
-			Inputs: []v1beta1.TaskResource{
-				{
-					ResourceDeclaration: taskv1.ResourceDeclaration{
-						Name: inputSourceResourceName,
-						Type: taskv1.PipelineResourceTypeGit,
-					},
-				},
+		Workspaces: []v1beta1.WorkspaceDeclaration{sourceWorkspace()},
+		Params: []v1beta1.ParamSpec{
+			{
+				Description: "URL of the git repository",
+				Name:        inputParamSourceURL,
 			},
-			Outputs: []v1beta1.TaskResource{
-				{
-					ResourceDeclaration: taskv1.ResourceDeclaration{
-						Name: outputImageResourceName, // mapped from {{ .Build.OutputImage }}
-						Type: taskv1.PipelineResourceTypeImage,
-					},
+			{
+				Description: "The git revision to fetch",
+				Name:        inputParamSourceRevision,
+				Default: &v1beta1.ArrayOrString{
+					Type:      v1beta1.ParamTypeString,
+					StringVal: defaultSourceRevision,
 				},
 			},
-		},
-		Params: []v1beta1.ParamSpec{
+			{
+				Description: "URL of the image to push",
+				Name:        inputParamOutputImage,
+			},
 			{
 				Description: "Path to the Dockerfile",
 				Name:        inputParamDockerfile,
@@ -95,7 +161,31 @@ func GenerateTaskSpec(
 				},
 			},
 		},
-		Steps: []v1beta1.Step{},
+		Results: append(sourceResults(), []v1beta1.TaskResult{
+			{
+				Name:        resultImageDigest,
+				Description: "The digest of the image that was pushed",
+			},
+			{
+				Name:        resultImageSize,
+				Description: "The compressed size in bytes of the image that was pushed",
+			},
+		}...),
+		Steps: []v1beta1.Step{
+			sourceStep(cfg, build),
+		},
+	}
+
+	if err := utils.ValidateStrategyPlaceholders(strategy); err != nil {
+		return nil, err
+	}
+
+	if err := utils.ValidateParameters(parameters, utils.GetBuildParameters(build)); err != nil {
+		return nil, err
+	}
+
+	for _, parameter := range parameters {
+		generatedTaskSpec.Params = append(generatedTaskSpec.Params, getTaskParamSpec(parameter))
 	}
 
 	if build.Spec.BuilderImage != nil {
@@ -112,19 +202,42 @@ func GenerateTaskSpec(
 
 	var vols []corev1.Volume
 
+	resolver := getPlaceholderResolver(parameters)
+
 	for _, containerValue := range buildSteps {
 
 		var taskCommand []string
 		for _, buildStrategyCommandPart := range containerValue.Command {
-			taskCommand = append(taskCommand, getStringTransformations(buildStrategyCommandPart))
+			taskCommandPart, err := resolver.Resolve(buildStrategyCommandPart)
+			if err != nil {
+				return nil, err
+			}
+			taskCommand = append(taskCommand, taskCommandPart)
 		}
 
 		var taskArgs []string
 		for _, buildStrategyArgPart := range containerValue.Args {
-			taskArgs = append(taskArgs, getStringTransformations(buildStrategyArgPart))
+			taskArgPart, err := resolver.Resolve(buildStrategyArgPart)
+			if err != nil {
+				return nil, err
+			}
+			taskArgs = append(taskArgs, taskArgPart)
 		}
 
-		taskImage := getStringTransformations(containerValue.Image)
+		var taskEnv []corev1.EnvVar
+		for _, buildStrategyEnv := range containerValue.Env {
+			value, err := resolver.Resolve(buildStrategyEnv.Value)
+			if err != nil {
+				return nil, err
+			}
+			buildStrategyEnv.Value = value
+			taskEnv = append(taskEnv, buildStrategyEnv)
+		}
+
+		taskImage, err := resolver.Resolve(containerValue.Image)
+		if err != nil {
+			return nil, err
+		}
 
 		step := v1beta1.Step{
 			Container: corev1.Container{
@@ -136,7 +249,7 @@ func GenerateTaskSpec(
 				SecurityContext: containerValue.SecurityContext,
 				WorkingDir:      containerValue.WorkingDir,
 				Resources:       containerValue.Resources,
-				Env:             containerValue.Env,
+				Env:             taskEnv,
 			},
 		}
 
@@ -168,6 +281,25 @@ func GenerateTaskSpec(
 		}
 	}
 
+	// scanning the output image after all steps that push it, a vulnerable image is not signed
+	if policy := getVulnerabilityPolicy(build, strategy); policy != nil {
+		if err := AmendTaskSpecWithVulnerabilityScan(cfg, &generatedTaskSpec, policy); err != nil {
+			return nil, err
+		}
+	}
+
+	// generating the SBOM of the output image after all steps that push it
+	if utils.IsSBOMDefined(build) {
+		if err := AmendTaskSpecWithSBOM(cfg, &generatedTaskSpec, build); err != nil {
+			return nil, err
+		}
+	}
+
+	// signing the output image after all steps that push it
+	if utils.IsSigningDefined(build) {
+		AmendTaskSpecWithSigning(cfg, &generatedTaskSpec, build)
+	}
+
 	return &generatedTaskSpec, nil
 }
 
@@ -176,23 +308,18 @@ func GenerateTaskRun(
 	build *buildv1alpha1.Build,
 	buildRun *buildv1alpha1.BuildRun,
 	serviceAccountName string,
-	buildSteps []buildv1alpha1.BuildStep,
+	strategy buildv1alpha1.BuilderStrategy,
 ) (*v1beta1.TaskRun, error) {
 
-	revision := "master"
-	if build.Spec.Source.Revision != nil {
-		revision = *build.Spec.Source.Revision
-	}
+	// merge the overrides of the buildRun into the build
+	build = applyBuildRunOverrides(build, buildRun)
 
-	// retrieve expected imageURL form build or buildRun
-	var ImageURL string
-	if buildRun.Spec.Output != nil {
-		ImageURL = buildRun.Spec.Output.ImageURL
-	} else {
-		ImageURL = build.Spec.Output.ImageURL
+	taskSpec, err := GenerateTaskSpec(cfg, build, buildRun, strategy)
+	if err != nil {
+		return nil, err
 	}
 
-	taskSpec, err := GenerateTaskSpec(cfg, build, buildRun, buildSteps)
+	digest, err := strategyDigest(strategy)
 	if err != nil {
 		return nil, err
 	}
@@ -207,59 +334,50 @@ func GenerateTaskRun(
 				buildv1alpha1.LabelBuildRun:           buildRun.Name,
 				buildv1alpha1.LabelBuildRunGeneration: strconv.FormatInt(buildRun.Generation, 10),
 			},
+			Annotations: map[string]string{
+				buildv1alpha1.AnnotationBuildRunStrategyDigest: digest,
+			},
 		},
 		Spec: v1beta1.TaskRunSpec{
 			ServiceAccountName: serviceAccountName,
 			TaskSpec:           taskSpec,
-			Resources: &v1beta1.TaskRunResources{
-				Inputs: []v1beta1.TaskResourceBinding{
-					{
-						PipelineResourceBinding: v1beta1.PipelineResourceBinding{
-							Name: inputSourceResourceName,
-							ResourceSpec: &taskv1.PipelineResourceSpec{
-								Type: taskv1.PipelineResourceTypeGit,
-								Params: []taskv1.ResourceParam{
-									{
-										Name:  inputGitSourceURL,
-										Value: build.Spec.Source.URL,
-									},
-									{
-										Name:  inputGitSourceRevision,
-										Value: revision,
-									},
-								},
-							},
-						},
-					},
-				},
-				Outputs: []v1beta1.TaskResourceBinding{
-					{
-						PipelineResourceBinding: v1beta1.PipelineResourceBinding{
-							Name: outputImageResourceName,
-							ResourceSpec: &taskv1.PipelineResourceSpec{
-								Type: taskv1.PipelineResourceTypeImage,
-								Params: []taskv1.ResourceParam{
-									{
-										Name:  outputImageResourceURL,
-										Value: ImageURL,
-									},
-								},
-							},
-						},
-					},
+			Workspaces: []v1beta1.WorkspaceBinding{
+				{
+					Name:     sourceWorkspaceName,
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
 				},
 			},
 		},
 	}
 
 	// assign the timeout
-	if buildRun.Spec.Timeout != nil {
-		expectedTaskRun.Spec.Timeout = buildRun.Spec.Timeout
-	} else if build.Spec.Timeout != nil {
+	if build.Spec.Timeout != nil {
 		expectedTaskRun.Spec.Timeout = build.Spec.Timeout
 	}
 
-	var inputParams []v1beta1.Param
+	inputParams := []v1beta1.Param{
+		{
+			Name: inputParamSourceURL,
+			Value: v1beta1.ArrayOrString{
+				Type:      v1beta1.ParamTypeString,
+				StringVal: build.Spec.Source.URL,
+			},
+		},
+		{
+			Name: inputParamSourceRevision,
+			Value: v1beta1.ArrayOrString{
+				Type:      v1beta1.ParamTypeString,
+				StringVal: getSourceRevision(build),
+			},
+		},
+		{
+			Name: inputParamOutputImage,
+			Value: v1beta1.ArrayOrString{
+				Type:      v1beta1.ParamTypeString,
+				StringVal: build.Spec.Output.ImageURL,
+			},
+		},
+	}
 	if build.Spec.BuilderImage != nil {
 		inputParams = append(inputParams, v1beta1.Param{
 			Name: inputParamBuilderImage,
@@ -288,6 +406,13 @@ func GenerateTaskRun(
 		})
 	}
 
+	for _, parameter := range utils.GetBuildParameters(build) {
+		inputParams = append(inputParams, v1beta1.Param{
+			Name:  taskParamName(parameter.Name),
+			Value: getTaskParamValue(parameter),
+		})
+	}
+
 	expectedTaskRun.Spec.Params = inputParams
 	return expectedTaskRun, nil
 }
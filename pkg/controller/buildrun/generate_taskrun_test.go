@@ -62,38 +62,339 @@ var _ = Describe("GenerateTaskrun", func() {
 				Expect(err).To(BeNil())
 
 				expectedCommandOrArg = []string{
-					"bud", "--tag=$(outputs.resources.image.url)", fmt.Sprintf("--file=$(inputs.params.%s)", "DOCKERFILE"), fmt.Sprintf("$(inputs.params.%s)", "CONTEXT_DIR"),
+					"bud", "--tag=$(inputs.params.OUTPUT_IMAGE)", fmt.Sprintf("--file=$(inputs.params.%s)", "DOCKERFILE"), fmt.Sprintf("$(inputs.params.%s)", "CONTEXT_DIR"),
 				}
 			})
 
 			JustBeforeEach(func() {
-				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps)
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
 				Expect(err).To(BeNil())
 			})
 
 			It("should ensure IMAGE is replaced by builder image when needed.", func() {
-				Expect(got.Steps[0].Container.Image).To(Equal("quay.io/buildah/stable:latest"))
+				Expect(got.Steps[1].Container.Image).To(Equal("quay.io/buildah/stable:latest"))
 			})
 
 			It("should ensure command replacements happen when needed", func() {
-				Expect(got.Steps[0].Container.Command[0]).To(Equal("/usr/bin/buildah"))
+				Expect(got.Steps[1].Container.Command[0]).To(Equal("/usr/bin/buildah"))
 			})
 
 			It("should ensure resource replacements happen for the first step", func() {
-				Expect(got.Steps[0].Container.Resources).To(Equal(ctl.LoadCustomResources("500m", "1Gi")))
+				Expect(got.Steps[1].Container.Resources).To(Equal(ctl.LoadCustomResources("500m", "1Gi")))
 			})
 
 			It("should ensure resource replacements happen for the second step", func() {
-				Expect(got.Steps[1].Container.Resources).To(Equal(ctl.LoadCustomResources("100m", "65Mi")))
+				Expect(got.Steps[2].Container.Resources).To(Equal(ctl.LoadCustomResources("100m", "65Mi")))
 			})
 
 			It("should ensure arg replacements happen when needed", func() {
-				Expect(got.Steps[0].Container.Args).To(Equal(expectedCommandOrArg))
+				Expect(got.Steps[1].Container.Args).To(Equal(expectedCommandOrArg))
 			})
 
 			It("should ensure top level volumes are populated", func() {
 				Expect(len(got.Volumes)).To(Equal(1))
 			})
+
+			It("should declare the results for the image digest and size", func() {
+				Expect(got.Results).To(ContainElements(
+					v1beta1.TaskResult{Name: "shp-image-digest", Description: "The digest of the image that was pushed"},
+					v1beta1.TaskResult{Name: "shp-image-size", Description: "The compressed size in bytes of the image that was pushed"},
+				))
+			})
+
+			It("should fetch the source into the source workspace in the first step", func() {
+				Expect(got.Workspaces).To(Equal([]v1beta1.WorkspaceDeclaration{
+					{Name: "source", Description: "The directory into which the source is fetched", MountPath: "/workspace/source"},
+				}))
+				Expect(got.Steps[0].Container.Name).To(Equal("source-fetch"))
+				Expect(got.Steps[0].Container.Image).To(Equal(config.NewDefaultConfig().GitContainerImage))
+				Expect(got.Steps[0].Container.Env).To(Equal([]corev1.EnvVar{
+					{Name: "SOURCE_URL", Value: "$(inputs.params.SOURCE_URL)"},
+					{Name: "SOURCE_REVISION", Value: "$(inputs.params.SOURCE_REVISION)"},
+				}))
+				Expect(got.Steps[0].Container.Args[1]).To(ContainSubstring("git rev-parse HEAD >$(results.shp-source-commit-sha.path)"))
+				Expect(got.Steps[0].Container.Args[1]).To(ContainSubstring("--format=%an >$(results.shp-source-commit-author.path)"))
+				Expect(got.Steps[0].Container.Args[1]).To(ContainSubstring("--format=%cI >$(results.shp-source-commit-timestamp.path)"))
+				Expect(got.Results).To(ContainElements(
+					v1beta1.TaskResult{Name: "shp-source-commit-sha", Description: "The SHA of the commit that was checked out"},
+					v1beta1.TaskResult{Name: "shp-source-commit-author", Description: "The author of the commit that was checked out"},
+					v1beta1.TaskResult{Name: "shp-source-commit-timestamp", Description: "The time in RFC3339 format when the commit that was checked out was created"},
+				))
+			})
+
+			It("should not use pipeline resources", func() {
+				Expect(got.Resources).To(BeNil())
+			})
+
+			It("should ensure result path replacements happen when needed", func() {
+				Expect(got.Steps[2].Container.Args).To(ContainElement("--digestfile=$(results.shp-image-digest.path)"))
+			})
+		})
+		Context("when proxy settings are defined", func() {
+			var cfg *config.Config
+
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+
+				cfg = config.NewDefaultConfig()
+				cfg.SourceProxy = config.ProxyConfig{
+					HTTPProxy:  "http://proxy.example.com:3128",
+					HTTPSProxy: "http://proxy.example.com:3128",
+				}
+			})
+
+			It("should pass the controller defaults to the source step", func() {
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[0].Container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"},
+					corev1.EnvVar{Name: "http_proxy", Value: "http://proxy.example.com:3128"},
+					corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+					corev1.EnvVar{Name: "https_proxy", Value: "http://proxy.example.com:3128"},
+				))
+				for _, env := range got.Steps[0].Container.Env {
+					Expect(env.Name).ToNot(Equal("NO_PROXY"))
+				}
+			})
+
+			It("should let the build override the controller defaults", func() {
+				build.Spec.Source.HTTPSProxy = "http://build-proxy.example.com:8080"
+				build.Spec.Source.NoProxy = ".cluster.local"
+
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[0].Container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"},
+					corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://build-proxy.example.com:8080"},
+					corev1.EnvVar{Name: "https_proxy", Value: "http://build-proxy.example.com:8080"},
+					corev1.EnvVar{Name: "NO_PROXY", Value: ".cluster.local"},
+					corev1.EnvVar{Name: "no_proxy", Value: ".cluster.local"},
+				))
+			})
+
+			It("should not pass the proxy settings to the strategy steps", func() {
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[1].Container.Env).To(BeEmpty())
+			})
+		})
+
+		Context("when the build signs its output", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+			})
+
+			It("should not sign the image by default", func() {
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				for _, step := range got.Steps {
+					Expect(step.Container.Name).ToNot(Equal("sign-image"))
+				}
+			})
+
+			It("should append a step that signs the output image with the key of the secret", func() {
+				build.Spec.Output.Signing = &buildv1alpha1.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "signing-key"},
+				}
+
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				step := got.Steps[len(got.Steps)-1]
+				Expect(step.Container.Name).To(Equal("sign-image"))
+				Expect(step.Container.Image).To(Equal("gcr.io/projectsigstore/cosign:v1.5.1-dev"))
+				Expect(step.Container.Command).To(Equal([]string{"sh"}))
+				Expect(step.Container.Args[1]).To(ContainSubstring(`DIGEST="$(cat "$(results.shp-image-digest.path)" 2>/dev/null || true)"`))
+				Expect(step.Container.Args[1]).To(HaveSuffix(`exec cosign sign --key=/workspace/signing/cosign.key "$IMAGE"`))
+				Expect(step.Container.Env).To(ContainElement(corev1.EnvVar{
+					Name: "COSIGN_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "signing-key"},
+							Key:                  "cosign.password",
+						},
+					},
+				}))
+
+				Expect(got.Volumes).To(ContainElement(corev1.Volume{
+					Name: "shp-signing-key",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "signing-key",
+							Items:      []corev1.KeyToPath{{Key: "cosign.key", Path: "cosign.key"}},
+						},
+					},
+				}))
+			})
+		})
+
+		Context("when the build or its strategy has a vulnerability policy", func() {
+			var cfg *config.Config
+
+			BeforeEach(func() {
+				cfg = config.NewDefaultConfig()
+				cfg.TrivyContainerImage = "registry.example.com/trivy-offline:0.20.0"
+
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+			})
+
+			It("should not scan the image by default", func() {
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				for _, step := range got.Steps {
+					Expect(step.Container.Name).ToNot(Equal("scan-image"))
+				}
+			})
+
+			It("should append a step that scans the image with the policy of the build", func() {
+				build.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{Severity: buildv1alpha1.VulnerabilitySeverityHigh}
+				buildStrategy.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{Severity: buildv1alpha1.VulnerabilitySeverityLow}
+
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				step := got.Steps[len(got.Steps)-1]
+				Expect(step.Container.Name).To(Equal("scan-image"))
+				Expect(step.Container.Image).To(Equal("registry.example.com/trivy-offline:0.20.0"))
+				Expect(step.Container.Args[1]).To(ContainSubstring(`DIGEST="$(cat "$(results.shp-image-digest.path)" 2>/dev/null || true)"`))
+				Expect(step.Container.Args[1]).To(ContainSubstring(`trivy image --skip-update --offline-scan --no-progress --format json --output /tmp/vulnerabilities.json "$IMAGE"`))
+				Expect(step.Container.Args[1]).To(ContainSubstring(`>"$(results.shp-vulnerabilities.path)"`))
+				Expect(step.Container.Args[1]).To(ContainSubstring("if [ $((CRITICAL + HIGH)) -gt 0 ]; then"))
+				Expect(step.Container.Args[1]).To(ContainSubstring("exit 3"))
+				Expect(got.Results).To(ContainElement(v1beta1.TaskResult{
+					Name:        "shp-vulnerabilities",
+					Description: "The number of vulnerabilities of the image that was pushed by severity",
+				}))
+			})
+
+			It("should use the policy of the strategy if the build has none", func() {
+				buildStrategy.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{
+					Severity:      buildv1alpha1.VulnerabilitySeverityLow,
+					IgnoreUnfixed: true,
+				}
+
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				step := got.Steps[len(got.Steps)-1]
+				Expect(step.Container.Args[1]).To(ContainSubstring(`--output /tmp/vulnerabilities.json --ignore-unfixed "$IMAGE"`))
+				Expect(step.Container.Args[1]).To(ContainSubstring("if [ $((CRITICAL + HIGH + MEDIUM + LOW)) -gt 0 ]; then"))
+			})
+
+			It("should scan the image before it is signed", func() {
+				build.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{Severity: buildv1alpha1.VulnerabilitySeverityCritical}
+				build.Spec.Output.Signing = &buildv1alpha1.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "signing-key"},
+				}
+
+				got, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+				Expect(got.Steps[len(got.Steps)-2].Container.Name).To(Equal("scan-image"))
+				Expect(got.Steps[len(got.Steps)-1].Container.Name).To(Equal("sign-image"))
+			})
+
+			It("should fail for an unknown severity", func() {
+				build.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{Severity: "Severe"}
+
+				_, err = buildrunCtl.GenerateTaskSpec(cfg, build, buildRun, buildStrategy)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail if no image with a vulnerability database is configured", func() {
+				build.Spec.VulnerabilityPolicy = &buildv1alpha1.VulnerabilityPolicy{Severity: buildv1alpha1.VulnerabilitySeverityHigh}
+
+				_, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(MatchError("the vulnerability policy cannot be enforced, because no container image with a vulnerability database is configured"))
+			})
+		})
+
+		Context("when the build generates an SBOM", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+			})
+
+			It("should append steps that generate an SPDX SBOM of the image and attach it", func() {
+				build.Spec.Output.SBOM = &buildv1alpha1.ImageSBOM{}
+
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				generate, attach := got.Steps[len(got.Steps)-2], got.Steps[len(got.Steps)-1]
+				Expect(generate.Container.Name).To(Equal("generate-sbom"))
+				Expect(generate.Container.Image).To(Equal("docker.io/anchore/syft:v0.59.0-debug"))
+				Expect(generate.Container.Command).To(Equal([]string{"sh"}))
+				Expect(generate.Container.Args[1]).To(ContainSubstring(`DIGEST="$(cat "$(results.shp-image-digest.path)" 2>/dev/null || true)"`))
+				Expect(generate.Container.Args[1]).To(HaveSuffix(`exec /syft packages "registry:$IMAGE" --output=spdx-json --file=/workspace/sbom/sbom.json`))
+				Expect(attach.Container.Name).To(Equal("attach-sbom"))
+				Expect(attach.Container.Image).To(Equal("gcr.io/projectsigstore/cosign:v1.5.1-dev"))
+				Expect(attach.Container.Command).To(Equal([]string{"sh"}))
+				Expect(attach.Container.Args[1]).To(ContainSubstring(`DIGEST="$(cat "$(results.shp-image-digest.path)" 2>/dev/null || true)"`))
+				Expect(attach.Container.Args[1]).To(HaveSuffix(`exec cosign attach sbom --sbom=/workspace/sbom/sbom.json --type=spdx "$IMAGE"`))
+
+				Expect(got.Volumes).To(ContainElement(corev1.Volume{
+					Name:         "shp-sbom",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}))
+			})
+
+			It("should scan the source in the CycloneDX format if the build requests it", func() {
+				build.Spec.Output.SBOM = &buildv1alpha1.ImageSBOM{
+					Format: buildv1alpha1.SBOMFormatCycloneDX,
+					Scan:   buildv1alpha1.SBOMScanSource,
+				}
+
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+
+				generate, attach := got.Steps[len(got.Steps)-2], got.Steps[len(got.Steps)-1]
+				Expect(generate.Container.Args).To(Equal([]string{"-c", `exec /syft packages "dir:$(inputs.params.CONTEXT_DIR)" --output=cyclonedx-json --file=/workspace/sbom/sbom.json`}))
+				Expect(attach.Container.Args[1]).To(HaveSuffix(`--type=cyclonedx "$IMAGE"`))
+			})
+
+			It("should sign the image after the SBOM is attached", func() {
+				build.Spec.Output.SBOM = &buildv1alpha1.ImageSBOM{}
+				build.Spec.Output.Signing = &buildv1alpha1.ImageSigning{
+					SecretRef: corev1.LocalObjectReference{Name: "signing-key"},
+				}
+
+				got, err = buildrunCtl.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy)
+				Expect(err).To(BeNil())
+				Expect(got.Steps[len(got.Steps)-2].Container.Name).To(Equal("attach-sbom"))
+				Expect(got.Steps[len(got.Steps)-1].Container.Name).To(Equal("sign-image"))
+			})
 		})
 	})
 
@@ -142,7 +443,7 @@ var _ = Describe("GenerateTaskrun", func() {
 			})
 
 			JustBeforeEach(func() {
-				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy.Spec.BuildSteps)
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
 				Expect(err).To(BeNil())
 			})
 
@@ -154,31 +455,19 @@ var _ = Describe("GenerateTaskrun", func() {
 				Expect(got.Labels[buildv1alpha1.LabelBuildRun]).To(Equal(buildRun.Name))
 			})
 
-			It("should ensure generated TaskRun's input and output resources are correct", func() {
-				inputResources := got.Spec.Resources.Inputs
-				for _, inputResource := range inputResources {
-					Expect(inputResource.ResourceSpec.Type).To(Equal(v1beta1.PipelineResourceTypeGit))
-					params := inputResource.ResourceSpec.Params
-					for _, param := range params {
-						if param.Name == "url" {
-							Expect(param.Value).To(Equal(url))
-						}
-						if param.Name == "revision" {
-							Expect(param.Value).To(Equal(revision))
-						}
-					}
-				}
+			It("should ensure generated TaskRun's source, revision and output image params are correct", func() {
+				Expect(got.Spec.Params).To(ContainElements(
+					v1beta1.Param{Name: "SOURCE_URL", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: url}},
+					v1beta1.Param{Name: "SOURCE_REVISION", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: revision}},
+					v1beta1.Param{Name: "OUTPUT_IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: outputPath}},
+				))
+			})
 
-				outputResources := got.Spec.Resources.Outputs
-				for _, outputResource := range outputResources {
-					Expect(outputResource.ResourceSpec.Type).To(Equal(v1beta1.PipelineResourceTypeImage))
-					params := outputResource.ResourceSpec.Params
-					for _, param := range params {
-						if param.Name == "url" {
-							Expect(param.Value).To(Equal(outputPath))
-						}
-					}
-				}
+			It("should bind the source workspace to an empty directory", func() {
+				Expect(got.Spec.Resources).To(BeNil())
+				Expect(got.Spec.Workspaces).To(Equal([]v1beta1.WorkspaceBinding{
+					{Name: "source", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}))
 			})
 
 			It("should ensure resource replacements happen when needed", func() {
@@ -192,12 +481,22 @@ var _ = Describe("GenerateTaskrun", func() {
 						corev1.ResourceMemory: resource.MustParse("2Gi"),
 					},
 				}
-				Expect(got.Spec.TaskSpec.Steps[0].Resources).To(Equal(expectedResourceOrArg))
+				Expect(got.Spec.TaskSpec.Steps[1].Resources).To(Equal(expectedResourceOrArg))
 			})
 
 			It("should have no timeout set", func() {
 				Expect(got.Spec.Timeout).To(BeNil())
 			})
+
+			It("should annotate the TaskRun with the digest of the strategy steps", func() {
+				digest := got.Annotations[buildv1alpha1.AnnotationBuildRunStrategyDigest]
+				Expect(digest).To(HavePrefix("sha256:"))
+
+				buildStrategy.Spec.BuildSteps[0].Args = append(buildStrategy.Spec.BuildSteps[0].Args, "--quiet")
+				changed, err := buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+				Expect(changed.Annotations[buildv1alpha1.AnnotationBuildRunStrategyDigest]).ToNot(Equal(digest))
+			})
 		})
 
 		Context("when the taskrun is generated by special settings", func() {
@@ -213,7 +512,7 @@ var _ = Describe("GenerateTaskrun", func() {
 			})
 
 			JustBeforeEach(func() {
-				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy.Spec.BuildSteps)
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
 				Expect(err).To(BeNil())
 			})
 
@@ -251,7 +550,7 @@ var _ = Describe("GenerateTaskrun", func() {
 						corev1.ResourceMemory: resource.MustParse("2Gi"),
 					},
 				}
-				Expect(got.Spec.TaskSpec.Steps[0].Resources).To(Equal(expectedResourceOrArg))
+				Expect(got.Spec.TaskSpec.Steps[1].Resources).To(Equal(expectedResourceOrArg))
 			})
 
 			It("should have the timeout set correctly", func() {
@@ -272,7 +571,7 @@ var _ = Describe("GenerateTaskrun", func() {
 			})
 
 			JustBeforeEach(func() {
-				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy.Spec.BuildSteps)
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
 				Expect(err).To(BeNil())
 			})
 
@@ -295,21 +594,229 @@ var _ = Describe("GenerateTaskrun", func() {
 			})
 
 			JustBeforeEach(func() {
-				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy.Spec.BuildSteps)
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
 				Expect(err).To(BeNil())
 			})
 
 			It("should use the imageURL from the BuildRun", func() {
-				outputResources := got.Spec.Resources.Outputs
-				for _, outputResource := range outputResources {
-					Expect(outputResource.ResourceSpec.Type).To(Equal(v1beta1.PipelineResourceTypeImage))
-					params := outputResource.ResourceSpec.Params
-					for _, param := range params {
-						if param.Name == "url" {
-							Expect(param.Value).To(Equal(outputPathBuildRun))
-						}
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "OUTPUT_IMAGE",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: outputPathBuildRun},
+				}))
+			})
+		})
+
+		Context("when the build defines parameters", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithParameters))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategyWithParameters))
+				Expect(err).To(BeNil())
+			})
+
+			It("should replace the parameter placeholders in the image, command and env", func() {
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				step := got.Spec.TaskSpec.Steps[1]
+				Expect(step.Image).To(Equal("quay.io/buildah/stable:$(inputs.params.PARAM_buildah-tag)"))
+				Expect(step.Command).To(ContainElement("--storage-driver=$(inputs.params.PARAM_storage-driver)"))
+				Expect(step.Env[0].Value).To(Equal("$(inputs.params.PARAM_storage-driver)"))
+			})
+
+			It("should pass the parameter values to the TaskRun", func() {
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				var paramSpecNames []string
+				for _, paramSpec := range got.Spec.TaskSpec.Params {
+					paramSpecNames = append(paramSpecNames, paramSpec.Name)
+				}
+				Expect(paramSpecNames).To(ContainElement("PARAM_storage-driver"))
+				Expect(paramSpecNames).To(ContainElement("PARAM_buildah-tag"))
+
+				values := map[string]string{}
+				for _, param := range got.Spec.Params {
+					values[param.Name] = param.Value.StringVal
+				}
+				Expect(values["PARAM_storage-driver"]).To(Equal("vfs"))
+				Expect(values["PARAM_buildah-tag"]).To(Equal("v1.16"))
+			})
+
+			It("should use the default values of parameters that the build does not set", func() {
+				build.Spec.Parameters = &[]buildv1alpha1.Parameter{{Name: "storage-driver", Value: "vfs"}}
+
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				for _, paramSpec := range got.Spec.TaskSpec.Params {
+					if paramSpec.Name == "PARAM_buildah-tag" {
+						Expect(paramSpec.Default.StringVal).To(Equal("latest"))
 					}
 				}
+				for _, param := range got.Spec.Params {
+					Expect(param.Name).ToNot(Equal("PARAM_buildah-tag"))
+				}
+			})
+
+			It("should pass array parameters as array values", func() {
+				build.Spec.Parameters = &[]buildv1alpha1.Parameter{
+					{Name: "storage-driver", Value: "vfs"},
+					{Name: "build-args", Values: []string{"--build-arg=A=1", "--build-arg=B=2"}},
+				}
+
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Spec.TaskSpec.Steps[1].Command).To(ContainElement("$(inputs.params.PARAM_build-args)"))
+				for _, param := range got.Spec.Params {
+					if param.Name == "PARAM_build-args" {
+						Expect(param.Value.Type).To(Equal(v1beta1.ParamTypeArray))
+						Expect(param.Value.ArrayVal).To(Equal([]string{"--build-arg=A=1", "--build-arg=B=2"}))
+					}
+				}
+			})
+
+			It("should fail when the build sets a parameter that the strategy does not declare", func() {
+				parameters := append(*build.Spec.Parameters, buildv1alpha1.Parameter{Name: "unknown", Value: "foo"})
+				build.Spec.Parameters = &parameters
+
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("parameter \"unknown\" is not declared by the build strategy"))
+			})
+
+			It("should fail when the build sets a parameter that the strategy does not reference", func() {
+				buildStrategy.Spec.Parameters = append(buildStrategy.Spec.Parameters, buildv1alpha1.ParameterDefinition{Name: "unknown"})
+				parameters := append(*build.Spec.Parameters, buildv1alpha1.Parameter{Name: "unknown", Value: "foo"})
+				build.Spec.Parameters = &parameters
+
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the build strategy declares parameter \"unknown\", which no step references"))
+			})
+
+			It("should fail when the build does not set a required parameter", func() {
+				build.Spec.Parameters = &[]buildv1alpha1.Parameter{{Name: "buildah-tag", Value: "v1.16"}}
+
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("parameter \"storage-driver\" is required by the build strategy but not set"))
+			})
+
+			It("should fail when the strategy references a parameter that it does not declare", func() {
+				buildStrategy.Spec.Parameters = buildStrategy.Spec.Parameters[1:]
+
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the build strategy references parameter \"storage-driver\", which it does not declare"))
+			})
+		})
+
+		Context("when the strategy uses placeholders", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithOutput))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategySingleStep))
+				Expect(err).To(BeNil())
+			})
+
+			It("should keep escaped placeholders and placeholders that are not build placeholders", func() {
+				buildStrategy.Spec.BuildSteps[0].Args = []string{"-c", "echo $$(build.output.image) $(date) $(build.output.image)"}
+
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+				Expect(got.Spec.TaskSpec.Steps[1].Args).To(Equal([]string{"-c", "echo $(build.output.image) $(date) $(inputs.params.OUTPUT_IMAGE)"}))
+			})
+
+			It("should fail when the strategy uses an unknown placeholder", func() {
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategyWithUnknownPlaceholder))
+				Expect(err).To(BeNil())
+
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the build strategy references unknown placeholder \"$(build.outptu.image)\""))
+			})
+		})
+
+		Context("when the buildrun overrides the revision, builder image and parameters", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithParameters))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.BuildahBuildRunWithOverrides))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategyWithParameters))
+				Expect(err).To(BeNil())
+			})
+
+			JustBeforeEach(func() {
+				got, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+			})
+
+			It("should use the revision from the BuildRun", func() {
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "SOURCE_REVISION",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "feature-branch"},
+				}))
+			})
+
+			It("should use the builder image from the BuildRun", func() {
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "BUILDER_IMAGE",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "quay.io/buildah/stable:v1.17"},
+				}))
+			})
+
+			It("should merge the parameters of the Build and the BuildRun", func() {
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "PARAM_storage-driver",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "vfs"},
+				}))
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "PARAM_buildah-tag",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "v1.17"},
+				}))
+				Expect(got.Spec.Params).To(ContainElement(v1beta1.Param{
+					Name:  "PARAM_build-args",
+					Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeArray, ArrayVal: []string{"--build-arg=A=1"}},
+				}))
+			})
+
+			It("should not modify the Build", func() {
+				Expect(build.Spec.Source.Revision).To(BeNil())
+				Expect(build.Spec.BuilderImage).To(BeNil())
+				Expect(*build.Spec.Parameters).To(HaveLen(2))
+			})
+		})
+
+		Context("when the buildrun overrides a parameter that the strategy does not declare", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithParameters))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunYAML([]byte(test.BuildahBuildRunWithOverrides))
+				Expect(err).To(BeNil())
+				buildRun.Spec.Parameters = append(buildRun.Spec.Parameters, buildv1alpha1.Parameter{Name: "unknown", Value: "foo"})
+
+				buildStrategy, err = ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategyWithParameters))
+				Expect(err).To(BeNil())
+			})
+
+			It("should fail to generate the TaskRun", func() {
+				_, err = buildrunCtl.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("parameter \"unknown\" is not declared by the build strategy"))
 			})
 		})
 	})
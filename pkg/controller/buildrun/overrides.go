@@ -0,0 +1,89 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// applyBuildRunOverrides returns a copy of the Build with the overrides of the BuildRun merged
+// into its spec. The original Build is not modified.
+func applyBuildRunOverrides(build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) *buildv1alpha1.Build {
+	effectiveBuild := build.DeepCopy()
+
+	if buildRun.Spec.Output != nil {
+		effectiveBuild.Spec.Output = *overrideImage(&effectiveBuild.Spec.Output, buildRun.Spec.Output)
+	}
+
+	if buildRun.Spec.BuilderImage != nil {
+		effectiveBuild.Spec.BuilderImage = overrideImage(effectiveBuild.Spec.BuilderImage, buildRun.Spec.BuilderImage)
+	}
+
+	if buildRun.Spec.Revision != nil {
+		revision := *buildRun.Spec.Revision
+		effectiveBuild.Spec.Source.Revision = &revision
+	}
+
+	if buildRun.Spec.Timeout != nil {
+		effectiveBuild.Spec.Timeout = buildRun.Spec.Timeout.DeepCopy()
+	}
+
+	if buildRun.Spec.Retries != nil {
+		effectiveBuild.Spec.Retries = buildRun.Spec.Retries.DeepCopy()
+	}
+
+	if len(buildRun.Spec.Parameters) > 0 {
+		parameters := overrideParameters(effectiveBuild.Spec.Parameters, buildRun.Spec.Parameters)
+		effectiveBuild.Spec.Parameters = &parameters
+	}
+
+	return effectiveBuild
+}
+
+// overrideImage returns the image of the BuildRun, keeping the credentials, the tags, the signing,
+// the SBOM and the provenance of the Build image when the BuildRun does not define them
+func overrideImage(buildImage *buildv1alpha1.Image, buildRunImage *buildv1alpha1.Image) *buildv1alpha1.Image {
+	image := buildRunImage.DeepCopy()
+	if image.SecretRef == nil && buildImage != nil && buildImage.SecretRef != nil {
+		image.SecretRef = buildImage.SecretRef.DeepCopy()
+	}
+	if len(image.Tags) == 0 && buildImage != nil && len(buildImage.Tags) > 0 {
+		image.Tags = append([]string(nil), buildImage.Tags...)
+	}
+	if image.Signing == nil && buildImage != nil && buildImage.Signing != nil {
+		image.Signing = buildImage.Signing.DeepCopy()
+	}
+	if image.SBOM == nil && buildImage != nil && buildImage.SBOM != nil {
+		image.SBOM = buildImage.SBOM.DeepCopy()
+	}
+	if image.Provenance == nil && buildImage != nil && buildImage.Provenance != nil {
+		provenance := *buildImage.Provenance
+		image.Provenance = &provenance
+	}
+	return image
+}
+
+// overrideParameters returns the parameters of the Build, where the values of parameters with
+// the same name are replaced by the ones of the BuildRun, and other BuildRun parameters are added
+func overrideParameters(buildParameters *[]buildv1alpha1.Parameter, buildRunParameters []buildv1alpha1.Parameter) []buildv1alpha1.Parameter {
+	var parameters []buildv1alpha1.Parameter
+	if buildParameters != nil {
+		parameters = append(parameters, *buildParameters...)
+	}
+
+	for _, buildRunParameter := range buildRunParameters {
+		overridden := false
+		for i := range parameters {
+			if parameters[i].Name == buildRunParameter.Name {
+				parameters[i] = *buildRunParameter.DeepCopy()
+				overridden = true
+			}
+		}
+		if !overridden {
+			parameters = append(parameters, *buildRunParameter.DeepCopy())
+		}
+	}
+	return parameters
+}
@@ -0,0 +1,331 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	buildmetrics "github.com/shipwright-io/build/pkg/metrics"
+	"github.com/shipwright-io/build/pkg/registry"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	// errPlatformRetries is the error of a Build with platforms and retries, the TaskRuns of
+	// platforms are not retried
+	errPlatformRetries = errors.New("retries are not supported for a Build with platforms")
+
+	// errPlatformSigning is the error of a Build with platforms and signing, the image index
+	// that the output image points to cannot be signed
+	errPlatformSigning = errors.New("signing is not supported for a Build with platforms")
+
+	// errPlatformSBOM is the error of a Build with platforms and an SBOM, no SBOM can be
+	// attached to the image index that the output image points to
+	errPlatformSBOM = errors.New("SBOMs are not supported for a Build with platforms")
+)
+
+const (
+	// nodeLabelOS is the well-known label of the operating system of a node
+	nodeLabelOS = "kubernetes.io/os"
+
+	// nodeLabelArch is the well-known label of the architecture of a node
+	nodeLabelArch = "kubernetes.io/arch"
+)
+
+// hasPlatforms returns true if the BuildRun builds an image for every platform of its Build
+func hasPlatforms(buildRun *buildv1alpha1.BuildRun) bool {
+	return buildRun.Status.BuildSpec != nil && len(buildRun.Status.BuildSpec.Platforms) > 0
+}
+
+// isPlatformTaskRun returns true if the TaskRun builds the image of a platform of its BuildRun
+func isPlatformTaskRun(taskRun *v1beta1.TaskRun) bool {
+	_, ok := taskRun.Labels[buildv1alpha1.LabelBuildRunPlatform]
+	return ok
+}
+
+// platformLabel returns the platform in a form that is valid as label value and in tags, for
+// example linux-arm64 for linux/arm64
+func platformLabel(platform string) string {
+	return strings.ReplaceAll(platform, "/", "-")
+}
+
+// platformImage returns the URL that the image of the platform is pushed to, the output image
+// with the platform appended to its tag, for example app:latest-linux-arm64
+func platformImage(image string, platform string) (string, error) {
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	tag := ref.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+	return imageWithTag(image, tag+"-"+platformLabel(platform)), nil
+}
+
+// createPlatformTaskRuns creates a TaskRun for every platform of the Build. Every TaskRun runs
+// on a node of its platform, and pushes the image of the platform to its own tag.
+func (r *ReconcileBuildRun) createPlatformTaskRuns(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) error {
+	effectiveBuild := applyBuildRunOverrides(build, buildRun)
+	if effectiveBuild.Spec.Retries != nil {
+		return r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, errPlatformRetries.Error())
+	}
+	if effectiveBuild.Spec.Output.Signing != nil {
+		return r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, errPlatformSigning.Error())
+	}
+	if effectiveBuild.Spec.Output.SBOM != nil {
+		return r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, errPlatformSBOM.Error())
+	}
+
+	// the overrides of the BuildRun are already merged, so that its output does not replace the
+	// output image of the platform
+	platformBuildRun := buildRun.DeepCopy()
+	platformBuildRun.Spec.Output = nil
+
+	var results []buildv1alpha1.PlatformResult
+	var created []*v1beta1.TaskRun
+	for _, platform := range effectiveBuild.Spec.Platforms {
+		p, err := registry.ParsePlatform(platform)
+		if err != nil {
+			return r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, err.Error())
+		}
+		image, err := platformImage(effectiveBuild.Spec.Output.ImageURL, platform)
+		if err != nil {
+			return r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunGenerationFailed, err.Error())
+		}
+
+		platformBuild := effectiveBuild.DeepCopy()
+		platformBuild.Spec.Output.ImageURL = image
+
+		generatedTaskRun, err := r.createTaskRun(ctx, platformBuild, platformBuildRun)
+		if err != nil {
+			return handleError("Failed to generate the TaskRun of a platform", err, r.cancelPlatformTaskRuns(ctx, buildRun, created))
+		}
+		generatedTaskRun.Labels[buildv1alpha1.LabelBuildRunPlatform] = platformLabel(platform)
+		if generatedTaskRun.Spec.PodTemplate == nil {
+			generatedTaskRun.Spec.PodTemplate = &v1beta1.PodTemplate{}
+		}
+		if generatedTaskRun.Spec.PodTemplate.NodeSelector == nil {
+			generatedTaskRun.Spec.PodTemplate.NodeSelector = map[string]string{}
+		}
+		generatedTaskRun.Spec.PodTemplate.NodeSelector[nodeLabelOS] = p.OS
+		generatedTaskRun.Spec.PodTemplate.NodeSelector[nodeLabelArch] = p.Architecture
+
+		ctxlog.Info(ctx, "creating TaskRun of platform from BuildRun", namespace, buildRun.Namespace, name, generatedTaskRun.GenerateName, "BuildRun", buildRun.Name, "platform", platform)
+		if err := r.client.Create(ctx, generatedTaskRun); err != nil {
+			cancelErr := r.cancelPlatformTaskRuns(ctx, buildRun, created)
+			updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunCreationFailed, err.Error())
+			return handleError("Failed to create the TaskRun of a platform", err, cancelErr, updateErr)
+		}
+		created = append(created, generatedTaskRun)
+		results = append(results, buildv1alpha1.PlatformResult{Platform: platform, TaskRunRef: generatedTaskRun.Name, Image: image})
+	}
+
+	// the TaskRun of the first platform is the latest TaskRun, so that the BuildRun counts as
+	// started, the TaskRuns of all platforms are in the platforms of the status
+	recordTaskRun(buildRun, results[0].TaskRunRef)
+	buildRun.Status.Platforms = results
+	buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, fmt.Sprintf("the TaskRuns of %d platforms are created", len(results)))
+	if err := r.client.Status().Update(ctx, buildRun); err != nil {
+		// like for a single TaskRun, the error is ignored to not create the TaskRuns again, the
+		// reconciliation of the TaskRuns records them in the status
+		ctxlog.Error(ctx, err, "Failed to update BuildRun status is ignored", namespace, buildRun.Namespace, name, buildRun.Name)
+	}
+	return nil
+}
+
+// cancelPlatformTaskRuns cancels the TaskRuns of the platforms that were already created, when
+// the TaskRun of a further platform cannot be created and the BuildRun fails
+func (r *ReconcileBuildRun) cancelPlatformTaskRuns(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRuns []*v1beta1.TaskRun) error {
+	for _, taskRun := range taskRuns {
+		if err := r.cancelTaskRun(ctx, buildRun, taskRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcilePlatformTaskRuns updates the status of the BuildRun from the TaskRuns of all of its
+// platforms. The BuildRun fails as soon as one TaskRun fails, and the other TaskRuns are cancelled.
+// Once all of them succeeded, the image index of the images of all platforms is pushed.
+func (r *ReconcileBuildRun) reconcilePlatformTaskRuns(ctx context.Context, buildRun *buildv1alpha1.BuildRun) (reconcile.Result, error) {
+	if !hasPlatforms(buildRun) {
+		return reconcile.Result{}, nil
+	}
+
+	taskRunList := &v1beta1.TaskRunList{}
+	if err := r.client.List(ctx, taskRunList, &client.ListOptions{
+		Namespace:     buildRun.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{buildv1alpha1.LabelBuildRun: buildRun.Name}),
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	taskRuns := map[string]*v1beta1.TaskRun{}
+	for i := range taskRunList.Items {
+		if label, ok := taskRunList.Items[i].Labels[buildv1alpha1.LabelBuildRunPlatform]; ok {
+			taskRuns[label] = &taskRunList.Items[i]
+		}
+	}
+
+	var results []buildv1alpha1.PlatformResult
+	var succeeded, running []*v1beta1.TaskRun
+	var failed *v1beta1.TaskRun
+	var failedPlatform string
+	for _, platform := range buildRun.Status.BuildSpec.Platforms {
+		result := buildv1alpha1.PlatformResult{Platform: platform}
+		result.Image, _ = platformImage(buildRun.Status.BuildSpec.Output.ImageURL, platform)
+
+		taskRun, ok := taskRuns[platformLabel(platform)]
+		if ok {
+			result.TaskRunRef = taskRun.Name
+
+			if taskRun.Status.StartTime != nil && (buildRun.Status.StartTime == nil || taskRun.Status.StartTime.Before(buildRun.Status.StartTime)) {
+				buildRun.Status.StartTime = taskRun.Status.StartTime
+			}
+
+			switch condition := taskRun.Status.GetCondition(apis.ConditionSucceeded); {
+			case condition == nil:
+			case condition.IsTrue():
+				result.Digest = getImageDigest(taskRun)
+				succeeded = append(succeeded, taskRun)
+			case condition.IsFalse():
+				if failed == nil {
+					failed, failedPlatform = taskRun, platform
+				}
+			case condition.Reason == v1beta1.TaskRunReasonRunning.String():
+				running = append(running, taskRun)
+			}
+		}
+		results = append(results, result)
+	}
+	buildRun.Status.Platforms = results
+
+	var previousReason string
+	if previous := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded); previous != nil {
+		previousReason = previous.Reason
+	}
+
+	var status corev1.ConditionStatus
+	var reason, message string
+	switch {
+	case failed != nil:
+		status = corev1.ConditionFalse
+		reason, message = getTaskRunReasonAndMessage(ctx, failed, failed.Status.GetCondition(apis.ConditionSucceeded))
+		message = fmt.Sprintf("the TaskRun %s of the platform %s failed: %s", failed.Name, failedPlatform, message)
+		updateBuildRunSources(ctx, buildRun, failed)
+		updateBuildRunVulnerabilities(ctx, buildRun, failed)
+
+		for _, taskRun := range taskRuns {
+			if err := r.cancelTaskRun(ctx, buildRun, taskRun); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
+	case len(succeeded) == len(results):
+		status, reason, message = corev1.ConditionTrue, buildv1alpha1.BuildRunReasonSucceeded, fmt.Sprintf("the images of %d platforms are pushed", len(results))
+		updateBuildRunSources(ctx, buildRun, succeeded[0])
+		updateBuildRunVulnerabilities(ctx, buildRun, succeeded...)
+
+		if err := r.pushImageIndex(ctx, buildRun); err != nil {
+			ctxlog.Error(ctx, err, "failed to push the image index", namespace, buildRun.Namespace, name, buildRun.Name)
+			status, reason, message = corev1.ConditionFalse, buildv1alpha1.BuildRunReasonImageIndexFailed, err.Error()
+		} else if err := r.tagOutputImage(ctx, buildRun); err != nil {
+			ctxlog.Error(ctx, err, "failed to tag the output image", namespace, buildRun.Namespace, name, buildRun.Name)
+			status, reason, message = corev1.ConditionFalse, buildv1alpha1.BuildRunReasonTaggingFailed, err.Error()
+		} else if err := r.attestPlatformImages(ctx, buildRun, taskRuns); err != nil {
+			ctxlog.Error(ctx, err, "failed to push the provenance of the platform images", namespace, buildRun.Namespace, name, buildRun.Name)
+			status, reason, message = corev1.ConditionFalse, buildv1alpha1.BuildRunReasonProvenanceFailed, err.Error()
+		}
+		updateBuildRunSignature(buildRun)
+		updateBuildRunSBOM(buildRun)
+
+	case len(running) > 0:
+		status, reason, message = corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonRunning, fmt.Sprintf("the TaskRuns of %d of %d platforms are running", len(running), len(results))
+
+	default:
+		status, reason, message = corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, fmt.Sprintf("the TaskRuns of %d platforms are pending", len(results))
+	}
+	buildRun.Status.SetSucceededCondition(status, reason, message)
+
+	if status != corev1.ConditionUnknown && buildRun.Status.CompletionTime == nil {
+		now := metav1.Now()
+		buildRun.Status.CompletionTime = &now
+
+		if err := r.deleteGeneratedServiceAccount(ctx, buildRun); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if buildRun.Status.BuildSpec.StrategyRef != nil {
+			buildmetrics.BuildRunCountInc(buildRun.Status.BuildSpec.StrategyRef.Name)
+			buildmetrics.BuildRunCompletionObserve(
+				buildRun.Status.BuildSpec.StrategyRef.Name,
+				buildRun.Namespace,
+				buildRun.Status.CompletionTime.Time.Sub(buildRun.CreationTimestamp.Time),
+			)
+		}
+	}
+
+	ctxlog.Info(ctx, "updating buildRun status from the TaskRuns of its platforms", namespace, buildRun.Namespace, name, buildRun.Name)
+	if err := r.client.Status().Update(ctx, buildRun); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if state, ok := getCommitStatusState(status, reason); ok && reason != previousReason {
+		r.reportCommitStatus(ctx, buildRun, state, reason)
+	}
+	return reconcile.Result{}, nil
+}
+
+// pushImageIndex pushes the image index of the images of all platforms to the output image, and
+// records its digest in the output of the BuildRun
+func (r *ReconcileBuildRun) pushImageIndex(ctx context.Context, buildRun *buildv1alpha1.BuildRun) error {
+	output := buildRun.Status.BuildSpec.Output
+
+	credentials, err := r.getRegistryCredentials(ctx, buildRun.Namespace, output)
+	if err != nil {
+		return err
+	}
+
+	var images []registry.PlatformImage
+	for i := range buildRun.Status.Platforms {
+		result := &buildRun.Status.Platforms[i]
+		p, err := registry.ParsePlatform(result.Platform)
+		if err != nil {
+			return err
+		}
+
+		// the digest is resolved from the tag of the platform, if the strategy did not report it
+		if result.Digest == "" {
+			if result.Digest, err = r.registry.Resolve(ctx, result.Image, credentials); err != nil {
+				return err
+			}
+		}
+		images = append(images, registry.PlatformImage{Platform: p, Digest: result.Digest})
+	}
+
+	ctxlog.Info(ctx, "pushing the image index of the platforms", namespace, buildRun.Namespace, name, buildRun.Name, "image", output.ImageURL)
+	digest, err := r.registry.PushIndex(ctx, output.ImageURL, images, credentials)
+	if err != nil {
+		return err
+	}
+
+	buildRun.Status.Output = &buildv1alpha1.BuildRunOutput{
+		Image:  output.ImageURL,
+		Digest: digest,
+	}
+	return nil
+}
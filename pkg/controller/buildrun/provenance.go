@@ -0,0 +1,266 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/registry"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// provenanceStatementType is the type of the in-toto statement of the provenance
+	provenanceStatementType = "https://in-toto.io/Statement/v0.1"
+
+	// provenancePredicateType is the type of the SLSA provenance predicate of the statement
+	provenancePredicateType = "https://slsa.dev/provenance/v0.2"
+
+	// provenanceBuilderID identifies the controller as the builder of the image
+	provenanceBuilderID = "https://shipwright.io/build/controller"
+
+	// provenanceBuildType is the type of the build, a BuildRun of a Build
+	provenanceBuildType = "https://shipwright.io/build/BuildRun@v1alpha1"
+)
+
+// provenanceStatement is an in-toto statement with a SLSA provenance predicate
+type provenanceStatement struct {
+	Type          string              `json:"_type"`
+	PredicateType string              `json:"predicateType"`
+	Subject       []provenanceSubject `json:"subject"`
+	Predicate     provenancePredicate `json:"predicate"`
+}
+
+// provenanceSubject is the image that the provenance describes
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// provenancePredicate describes how the image was built
+type provenancePredicate struct {
+	Builder     provenanceBuilder     `json:"builder"`
+	BuildType   string                `json:"buildType"`
+	Invocation  provenanceInvocation  `json:"invocation"`
+	BuildConfig provenanceBuildConfig `json:"buildConfig"`
+	Metadata    provenanceMetadata    `json:"metadata"`
+	Materials   []provenanceMaterial  `json:"materials,omitempty"`
+}
+
+// provenanceBuilder identifies the builder
+type provenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+// provenanceInvocation holds the source and the parameters of the build
+type provenanceInvocation struct {
+	ConfigSource provenanceMaterial               `json:"configSource"`
+	Parameters   map[string]v1beta1.ArrayOrString `json:"parameters,omitempty"`
+}
+
+// provenanceBuildConfig holds the build strategy and the images of its steps
+type provenanceBuildConfig struct {
+	Strategy provenanceStrategy `json:"strategy"`
+	Steps    []provenanceStep   `json:"steps,omitempty"`
+}
+
+// provenanceStrategy identifies the build strategy and the content of its steps
+type provenanceStrategy struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// provenanceStep is a step of the TaskRun with the image that it ran
+type provenanceStep struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// provenanceMetadata holds the identity and the times of the build
+type provenanceMetadata struct {
+	BuildInvocationID string       `json:"buildInvocationId"`
+	BuildStartedOn    *metav1.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *metav1.Time `json:"buildFinishedOn,omitempty"`
+}
+
+// provenanceMaterial is a source or an image that the build used
+type provenanceMaterial struct {
+	URI        string            `json:"uri"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint,omitempty"`
+}
+
+// strategyDigest returns the sha256 digest of the steps of the build strategy, two TaskRuns
+// with the same digest ran the same steps
+func strategyDigest(strategy buildv1alpha1.BuilderStrategy) (string, error) {
+	data, err := json.Marshal(strategy.GetBuildSteps())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+// isProvenanceEnabled returns true if the Build of the BuildRun pushes a provenance attestation
+func isProvenanceEnabled(buildRun *buildv1alpha1.BuildRun) bool {
+	return buildRun.Status.BuildSpec != nil && buildRun.Status.BuildSpec.Output.Provenance != nil && *buildRun.Status.BuildSpec.Output.Provenance
+}
+
+// stepImage returns the image that a step ran, the container runtime reports it with a prefix
+// like docker-pullable:// and with its digest
+func stepImage(step v1beta1.StepState) (string, map[string]string) {
+	image := step.ImageID
+	if i := strings.Index(image, "://"); i >= 0 {
+		image = image[i+3:]
+	}
+	if i := strings.Index(image, "@sha256:"); i >= 0 {
+		return image, map[string]string{"sha256": image[i+len("@sha256:"):]}
+	}
+	return image, nil
+}
+
+// generateProvenance returns the provenance statement of the image with the digest that the
+// TaskRun of the BuildRun built
+func generateProvenance(buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, image string, digest string) ([]byte, error) {
+	buildSpec := buildRun.Status.BuildSpec
+
+	// the subject is the repository of the image, the digest identifies the image in it
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	subject := provenanceSubject{
+		Name:   fmt.Sprintf("%s/%s", ref.Registry, ref.Repository),
+		Digest: map[string]string{"sha256": strings.TrimPrefix(digest, "sha256:")},
+	}
+
+	source := provenanceMaterial{URI: "git+" + buildSpec.Source.URL}
+	for _, result := range buildRun.Status.Sources {
+		if result.Git != nil && result.Git.CommitSha != "" {
+			source.Digest = map[string]string{"sha1": result.Git.CommitSha}
+			break
+		}
+	}
+
+	strategy := provenanceStrategy{Kind: string(buildv1alpha1.NamespacedBuildStrategyKind), Digest: taskRun.Annotations[buildv1alpha1.AnnotationBuildRunStrategyDigest]}
+	if buildSpec.StrategyRef != nil {
+		strategy.Name = buildSpec.StrategyRef.Name
+		if buildSpec.StrategyRef.Kind != nil {
+			strategy.Kind = string(*buildSpec.StrategyRef.Kind)
+		}
+	}
+	source.EntryPoint = strategy.Name
+
+	parameters := map[string]v1beta1.ArrayOrString{}
+	for _, param := range taskRun.Spec.Params {
+		parameters[param.Name] = param.Value
+	}
+
+	materials := []provenanceMaterial{{URI: source.URI, Digest: source.Digest}}
+	var steps []provenanceStep
+	for _, step := range taskRun.Status.Steps {
+		stepImage, stepDigest := stepImage(step)
+		if stepImage == "" {
+			continue
+		}
+		steps = append(steps, provenanceStep{Name: step.Name, Image: stepImage})
+		materials = append(materials, provenanceMaterial{URI: "oci://" + stepImage, Digest: stepDigest})
+	}
+
+	statement := provenanceStatement{
+		Type:          provenanceStatementType,
+		PredicateType: provenancePredicateType,
+		Subject:       []provenanceSubject{subject},
+		Predicate: provenancePredicate{
+			Builder:   provenanceBuilder{ID: provenanceBuilderID},
+			BuildType: provenanceBuildType,
+			Invocation: provenanceInvocation{
+				ConfigSource: source,
+				Parameters:   parameters,
+			},
+			BuildConfig: provenanceBuildConfig{
+				Strategy: strategy,
+				Steps:    steps,
+			},
+			Metadata: provenanceMetadata{
+				BuildInvocationID: fmt.Sprintf("%s/%s/%s", buildRun.Namespace, buildRun.Name, taskRun.Name),
+				BuildStartedOn:    taskRun.Status.StartTime,
+				BuildFinishedOn:   taskRun.Status.CompletionTime,
+			},
+			Materials: materials,
+		},
+	}
+	return json.Marshal(statement)
+}
+
+// pushProvenance pushes the provenance attestation of the image with the digest that the
+// TaskRun built, and returns its reference. The digest is resolved from the image, if the
+// strategy did not report it.
+func (r *ReconcileBuildRun) pushProvenance(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, image string, digest string) (string, error) {
+	credentials, err := r.getRegistryCredentials(ctx, buildRun.Namespace, buildRun.Status.BuildSpec.Output)
+	if err != nil {
+		return "", err
+	}
+
+	if digest == "" {
+		if digest, err = r.registry.Resolve(ctx, image, credentials); err != nil {
+			return "", err
+		}
+	}
+
+	statement, err := generateProvenance(buildRun, taskRun, image, digest)
+	if err != nil {
+		return "", err
+	}
+
+	ctxlog.Info(ctx, "pushing the provenance attestation of the output image", namespace, buildRun.Namespace, name, buildRun.Name, "image", image, "digest", digest)
+	return r.registry.PushAttestation(ctx, image, digest, statement, credentials)
+}
+
+// attestOutputImage pushes the provenance attestation of the image that the TaskRun pushed and
+// records its reference in the status of the BuildRun, if the Build pushes one
+func (r *ReconcileBuildRun) attestOutputImage(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun) error {
+	if !isProvenanceEnabled(buildRun) || buildRun.Status.Output == nil {
+		return nil
+	}
+
+	provenance, err := r.pushProvenance(ctx, buildRun, taskRun, buildRun.Status.BuildSpec.Output.ImageURL, buildRun.Status.Output.Digest)
+	if err != nil {
+		return err
+	}
+	buildRun.Status.Output.Provenance = provenance
+	return nil
+}
+
+// attestPlatformImages pushes the provenance attestations of the images of all platforms and
+// records their references in the status of the BuildRun, if the Build pushes them. The image
+// index has no attestation of its own.
+func (r *ReconcileBuildRun) attestPlatformImages(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRuns map[string]*v1beta1.TaskRun) error {
+	if !isProvenanceEnabled(buildRun) {
+		return nil
+	}
+
+	for i := range buildRun.Status.Platforms {
+		platform := &buildRun.Status.Platforms[i]
+		taskRun, ok := taskRuns[platformLabel(platform.Platform)]
+		if !ok {
+			continue
+		}
+
+		provenance, err := r.pushProvenance(ctx, buildRun, taskRun, platform.Image, platform.Digest)
+		if err != nil {
+			return err
+		}
+		platform.Provenance = provenance
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// quotaLimits are the strictest limits of all ClusterBuildRunQuotas, nil if there is no limit
+type quotaLimits struct {
+	maxRunningPerNamespace *int32
+	maxRunning             *int32
+}
+
+// admitBuildRun applies the ClusterBuildRunQuotas before the TaskRun of the BuildRun is created.
+// It returns true if the TaskRun can be created, otherwise the BuildRun is queued with its
+// position in its status, and waits with the returned result.
+func (r *ReconcileBuildRun) admitBuildRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun) (bool, reconcile.Result, error) {
+	// without the custom resource definition of the quotas, there are no quotas
+	quotaList := &buildv1alpha1.ClusterBuildRunQuotaList{}
+	if err := r.client.List(ctx, quotaList); err != nil && !meta.IsNoMatchError(err) {
+		return false, reconcile.Result{}, err
+	}
+
+	limits := getQuotaLimits(quotaList.Items)
+	if limits.maxRunningPerNamespace == nil && limits.maxRunning == nil {
+		buildRun.Status.QueuePosition = nil
+		return true, reconcile.Result{}, nil
+	}
+
+	// the quotas apply to the BuildRuns of all namespaces
+	buildRunList := &buildv1alpha1.BuildRunList{}
+	if err := r.client.List(ctx, buildRunList); err != nil {
+		return false, reconcile.Result{}, err
+	}
+
+	running := map[string]int32{}
+	queued := []*buildv1alpha1.BuildRun{buildRun}
+	for i := range buildRunList.Items {
+		other := &buildRunList.Items[i]
+		if (other.Namespace == buildRun.Namespace && other.Name == buildRun.Name) || other.Status.CompletionTime != nil || other.Spec.IsCancelled() || other.DeletionTimestamp != nil {
+			continue
+		}
+
+		// an admitted BuildRun has the Build spec in its status before its TaskRun is created,
+		// it runs even if its TaskRun is not yet recorded
+		switch {
+		case other.Status.LatestTaskRunRef != nil || other.Status.BuildSpec != nil:
+			running[other.Namespace]++
+		case other.Status.QueuePosition != nil:
+			queued = append(queued, other)
+		}
+	}
+
+	position := getQueuePosition(limits, running, queued, buildRun)
+	if position == 0 {
+		buildRun.Status.QueuePosition = nil
+		return true, reconcile.Result{}, nil
+	}
+
+	if buildRun.Status.QueuePosition == nil || *buildRun.Status.QueuePosition != position {
+		buildRun.Status.QueuePosition = &position
+		buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, fmt.Sprintf("the BuildRun is queued at position %d by the ClusterBuildRunQuotas", position))
+		ctxlog.Info(ctx, "BuildRun is queued by the ClusterBuildRunQuotas", namespace, buildRun.Namespace, name, buildRun.Name, "position", position)
+		if err := r.client.Status().Update(ctx, buildRun); err != nil {
+			return false, reconcile.Result{}, err
+		}
+	}
+	return false, reconcile.Result{RequeueAfter: queueInterval}, nil
+}
+
+// getQuotaLimits returns the strictest limits of the quotas
+func getQuotaLimits(quotas []buildv1alpha1.ClusterBuildRunQuota) quotaLimits {
+	var limits quotaLimits
+	for _, quota := range quotas {
+		limits.maxRunningPerNamespace = minLimit(limits.maxRunningPerNamespace, quota.Spec.MaxRunningPerNamespace)
+		limits.maxRunning = minLimit(limits.maxRunning, quota.Spec.MaxRunning)
+	}
+	return limits
+}
+
+func minLimit(a *int32, b *int32) *int32 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// getQueuePosition admits the queued BuildRuns in their fair order as long as the limits allow it.
+// It returns zero if the BuildRun is admitted, otherwise its position among the BuildRuns that
+// are not admitted, starting with one.
+func getQueuePosition(limits quotaLimits, running map[string]int32, queued []*buildv1alpha1.BuildRun, buildRun *buildv1alpha1.BuildRun) int32 {
+	admitted := map[string]int32{}
+	var total int32
+	for _, count := range running {
+		total += count
+	}
+
+	var position int32
+	for _, candidate := range fairOrder(queued, running) {
+		fits := (limits.maxRunningPerNamespace == nil || running[candidate.Namespace]+admitted[candidate.Namespace] < *limits.maxRunningPerNamespace) &&
+			(limits.maxRunning == nil || total < *limits.maxRunning)
+
+		if fits {
+			if candidate == buildRun {
+				return 0
+			}
+			admitted[candidate.Namespace]++
+			total++
+			continue
+		}
+
+		position++
+		if candidate == buildRun {
+			return position
+		}
+	}
+	return position
+}
+
+// fairOrder orders the queued BuildRuns round robin across their namespaces, so that a namespace
+// with many BuildRuns does not starve the others. The namespaces with the fewest running
+// BuildRuns come first in every round. Within a namespace, BuildRuns with a higher priority
+// come first, then the older ones, so that the priority of a BuildRun does not affect other
+// namespaces.
+func fairOrder(queued []*buildv1alpha1.BuildRun, running map[string]int32) []*buildv1alpha1.BuildRun {
+	byNamespace := map[string][]*buildv1alpha1.BuildRun{}
+	var namespaces []string
+	for _, buildRun := range queued {
+		if _, ok := byNamespace[buildRun.Namespace]; !ok {
+			namespaces = append(namespaces, buildRun.Namespace)
+		}
+		byNamespace[buildRun.Namespace] = append(byNamespace[buildRun.Namespace], buildRun)
+	}
+
+	sort.Slice(namespaces, func(i, j int) bool {
+		if running[namespaces[i]] != running[namespaces[j]] {
+			return running[namespaces[i]] < running[namespaces[j]]
+		}
+		return namespaces[i] < namespaces[j]
+	})
+
+	for _, buildRuns := range byNamespace {
+		sort.Slice(buildRuns, func(i, j int) bool {
+			if getPriority(buildRuns[i]) != getPriority(buildRuns[j]) {
+				return getPriority(buildRuns[i]) > getPriority(buildRuns[j])
+			}
+			return isCreatedBefore(buildRuns[i], buildRuns[j])
+		})
+	}
+
+	ordered := make([]*buildv1alpha1.BuildRun, 0, len(queued))
+	for round := 0; len(ordered) < len(queued); round++ {
+		for _, ns := range namespaces {
+			if round < len(byNamespace[ns]) {
+				ordered = append(ordered, byNamespace[ns][round])
+			}
+		}
+	}
+	return ordered
+}
+
+// getPriority returns the priority of the BuildRun, zero by default
+func getPriority(buildRun *buildv1alpha1.BuildRun) int32 {
+	if buildRun.Spec.Priority == nil {
+		return 0
+	}
+	return *buildRun.Spec.Priority
+}
@@ -0,0 +1,60 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// updateBuildRunOutput records the image that was pushed by the TaskRun in the status of the
+// BuildRun, using the results that the build strategy wrote
+func updateBuildRunOutput(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun) {
+	if buildRun.Status.BuildSpec == nil {
+		return
+	}
+
+	output := &buildv1alpha1.BuildRunOutput{
+		Image: buildRun.Status.BuildSpec.Output.ImageURL,
+	}
+
+	for _, result := range taskRun.Status.TaskRunResults {
+		value := strings.TrimSpace(result.Value)
+		switch result.Name {
+		case resultImageDigest:
+			output.Digest = value
+		case resultImageSize:
+			// the runtime-image step pushes the image again, so that the size that the
+			// build strategy reported does not belong to the final image
+			if buildRun.Status.BuildSpec.Runtime != nil {
+				continue
+			}
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				ctxlog.Info(ctx, "ignoring invalid image size result", namespace, buildRun.Namespace, name, buildRun.Name, "value", value)
+				continue
+			}
+			output.Size = size
+		}
+	}
+
+	buildRun.Status.Output = output
+}
+
+// getImageDigest returns the digest of the image that the build strategy reported in the results
+// of the TaskRun, if any
+func getImageDigest(taskRun *v1beta1.TaskRun) string {
+	for _, result := range taskRun.Status.TaskRunResults {
+		if result.Name == resultImageDigest {
+			return strings.TrimSpace(result.Value)
+		}
+	}
+	return ""
+}
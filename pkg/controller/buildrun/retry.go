@@ -0,0 +1,137 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxBackoffDoublings limits the growth of the backoff, so that it does not overflow
+const maxBackoffDoublings = 10
+
+// recordTaskRun records a TaskRun of the BuildRun as its latest attempt
+func recordTaskRun(buildRun *buildv1alpha1.BuildRun, taskRunName string) {
+	buildRun.Status.LatestTaskRunRef = &taskRunName
+	if !isTaskRunOfBuildRun(buildRun, taskRunName) {
+		buildRun.Status.TaskRunRefs = append(buildRun.Status.TaskRunRefs, taskRunName)
+	}
+	buildRun.Status.Attempts = int32(len(buildRun.Status.TaskRunRefs))
+}
+
+// isTaskRunOfBuildRun returns true if the TaskRun is one of the recorded attempts of the BuildRun
+func isTaskRunOfBuildRun(buildRun *buildv1alpha1.BuildRun, taskRunName string) bool {
+	for _, ref := range buildRun.Status.TaskRunRefs {
+		if ref == taskRunName {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreviousAttempt returns true if the TaskRun is a recorded attempt of the BuildRun that was
+// retried, its events must not change the status of the BuildRun anymore
+func isPreviousAttempt(buildRun *buildv1alpha1.BuildRun, taskRunName string) bool {
+	latest := buildRun.Status.LatestTaskRunRef
+	return latest != nil && *latest != taskRunName && isTaskRunOfBuildRun(buildRun, taskRunName)
+}
+
+// isRetriable returns true if the BuildRun can retry its failed TaskRun. Only a TaskRun that
+// failed is retried, a timeout, a cancellation or a vulnerability policy violation is not, and
+// the retries must not be used up.
+func isRetriable(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, trCondition *apis.Condition) bool {
+	if trCondition.Status != corev1.ConditionFalse || buildRun.Spec.IsCancelled() || buildRun.Status.BuildSpec == nil {
+		return false
+	}
+	if reason, _ := getTaskRunReasonAndMessage(ctx, taskRun, trCondition); reason != buildv1alpha1.BuildRunReasonFailed {
+		return false
+	}
+
+	retries := buildRun.Status.BuildSpec.Retries
+	return retries != nil && getAttempts(buildRun) <= retries.Max
+}
+
+// getAttempts returns the number of TaskRuns of the BuildRun, a BuildRun that was created
+// before the attempts were recorded has one
+func getAttempts(buildRun *buildv1alpha1.BuildRun) int32 {
+	if buildRun.Status.Attempts > 0 {
+		return buildRun.Status.Attempts
+	}
+	return 1
+}
+
+// getRetryBackoff returns the time to wait between the failure of the TaskRun of the given
+// attempt and the start of the next TaskRun
+func getRetryBackoff(retries *buildv1alpha1.Retries, attempt int32) time.Duration {
+	if retries.Backoff == nil || retries.Backoff.Duration <= 0 {
+		return 0
+	}
+
+	doublings := attempt - 1
+	if doublings > maxBackoffDoublings {
+		doublings = maxBackoffDoublings
+	}
+	return retries.Backoff.Duration << uint(doublings)
+}
+
+// retryTaskRun creates a new TaskRun for the BuildRun once the backoff after the failed TaskRun
+// passed. Until then, the BuildRun has the reason Retrying and the request is requeued.
+func (r *ReconcileBuildRun) retryTaskRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun, failedTaskRun *v1beta1.TaskRun, trCondition *apis.Condition) (reconcile.Result, error) {
+	attempt := getAttempts(buildRun)
+	retries := buildRun.Status.BuildSpec.Retries
+
+	failedAt := trCondition.LastTransitionTime.Inner.Time
+	if failedTaskRun.Status.CompletionTime != nil {
+		failedAt = failedTaskRun.Status.CompletionTime.Time
+	}
+	if wait := time.Until(failedAt.Add(getRetryBackoff(retries, attempt))); wait > 0 {
+		message := fmt.Sprintf("TaskRun %s failed: %s, retrying in %s (attempt %d of %d)", failedTaskRun.Name, trCondition.Message, wait.Round(time.Second), attempt+1, retries.Max+1)
+		if condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded); condition == nil || condition.Reason != buildv1alpha1.BuildRunReasonRetrying {
+			buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonRetrying, message)
+			if err := r.client.Status().Update(ctx, buildRun); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		ctxlog.Info(ctx, "waiting for the retry of the failed TaskRun", namespace, buildRun.Namespace, name, buildRun.Name, "TaskRun", failedTaskRun.Name, "wait", wait.String())
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	// the retry uses the same Build spec as the previous attempts
+	build := &buildv1alpha1.Build{}
+	build.Name = buildRun.Spec.BuildRef.Name
+	build.Namespace = buildRun.Namespace
+	build.Generation, _ = strconv.ParseInt(buildRun.Labels[buildv1alpha1.LabelBuildGeneration], 10, 64)
+	build.Spec = *buildRun.Status.BuildSpec.DeepCopy()
+
+	generatedTaskRun, err := r.createTaskRun(ctx, build, buildRun)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	ctxlog.Info(ctx, "retrying failed TaskRun", namespace, buildRun.Namespace, name, buildRun.Name, "TaskRun", failedTaskRun.Name, "attempt", attempt+1)
+	if err := r.client.Create(ctx, generatedTaskRun); err != nil {
+		updateErr := r.updateBuildRunErrorStatus(ctx, buildRun, buildv1alpha1.BuildRunReasonTaskRunCreationFailed, err.Error())
+		return reconcile.Result{}, handleError("Failed to create the TaskRun of a retry", err, updateErr)
+	}
+
+	recordTaskRun(buildRun, generatedTaskRun.Name)
+	buildRun.Status.SetSucceededCondition(corev1.ConditionUnknown, buildv1alpha1.BuildRunReasonPending, fmt.Sprintf("TaskRun %s is created (attempt %d of %d)", generatedTaskRun.Name, buildRun.Status.Attempts, retries.Max+1))
+	if err := r.client.Status().Update(ctx, buildRun); err != nil {
+		// like for the first TaskRun, the error is ignored to not create another TaskRun, the
+		// reconciliation of the new TaskRun records it in the status
+		ctxlog.Error(ctx, err, "Failed to update BuildRun status is ignored", namespace, buildRun.Namespace, name, buildRun.Name)
+	}
+	return reconcile.Result{}, nil
+}
@@ -15,6 +15,7 @@ import (
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/controller/utils"
+	"github.com/shipwright-io/build/pkg/placeholder"
 	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	v1 "k8s.io/api/core/v1"
 )
@@ -70,6 +71,9 @@ ENTRYPOINT [ {{ renderEntrypoint .Spec.Runtime.Entrypoint }} ]
 
 	// defultShellImage default image for a simple shell instance.
 	defultShellImage = "busybox:latest"
+
+	// workspacePlaceholder placeholder for the directory of the source code in the runtime Dockerfile.
+	workspacePlaceholder = "workspace"
 )
 
 // rootUserID root's UID
@@ -129,16 +133,13 @@ func renderRuntimeDockerfile(b *buildv1alpha1.Build) (*bytes.Buffer, error) {
 	return dockerfile, nil
 }
 
-// runtimeDockerfileTransformations search and replace special variables `$()` in informed string.
-func runtimeDockerfileTransformations(b *buildv1alpha1.Build, str string) string {
+// runtimeDockerfileTransformations resolves the `$(workspace)` placeholder in informed string.
+func runtimeDockerfileTransformations(b *buildv1alpha1.Build, str string) (string, error) {
 	contextDir := getContextDir(b)
-	transformations := map[string]string{
-		"$(workspace)": path.Join(workspaceDir, contextDir),
-	}
-	for k, v := range transformations {
-		str = strings.ReplaceAll(str, k, v)
-	}
-	return str
+	resolver := placeholder.NewResolver(workspacePlaceholder, map[string]string{
+		workspacePlaceholder: path.Join(workspaceDir, contextDir),
+	})
+	return resolver.Resolve(str)
 }
 
 // getContextDir retrieve contextDir from Source, or empty string.
@@ -159,7 +160,10 @@ func runtimeDockerfileStep(b *buildv1alpha1.Build) (*v1beta1.Step, error) {
 	}
 	// appling known transformation to dockerfile payload, therefore operator variables are
 	// applicable to all parts of the runtime Dockerfile as well
-	dockerfileTransformed := runtimeDockerfileTransformations(b, dockerfile.String())
+	dockerfileTransformed, err := runtimeDockerfileTransformations(b, dockerfile.String())
+	if err != nil {
+		return nil, err
+	}
 
 	// using builder-image when defined, or falling back to a default
 	imageURL := defultShellImage
@@ -215,6 +219,7 @@ func runtimeBuildAndPushStep(b *buildv1alpha1.Build, kanikoImage string) *v1beta
 			fmt.Sprintf("--dockerfile=%s", runtimeDockerfile),
 			fmt.Sprintf("--context=%x", path.Join(workspaceDir, contextDir)),
 			fmt.Sprintf("--destination=%s", b.Spec.Output.ImageURL),
+			fmt.Sprintf("--digest-file=$(results.%s.path)", resultImageDigest),
 			"--snapshotMode=redo",
 		},
 	}
@@ -0,0 +1,150 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// sbomGenerateStepName is the name of the step that generates the SBOM
+	sbomGenerateStepName = "generate-sbom"
+
+	// sbomAttachStepName is the name of the step that attaches the SBOM to the output image
+	sbomAttachStepName = "attach-sbom"
+
+	// sbomVolumeName is the name of the volume that the SBOM is written to
+	sbomVolumeName = "shp-sbom"
+
+	// sbomDir is the directory in which the volume of the SBOM is mounted
+	sbomDir = "/workspace/sbom"
+)
+
+// sbomFile is the path of the SBOM in the volume of the SBOM
+var sbomFile = path.Join(sbomDir, "sbom.json")
+
+// sbomTool is how syft writes and cosign attaches an SBOM of a format
+type sbomTool struct {
+	syftOutput string
+	cosignType string
+}
+
+// sbomFormats maps the SBOM formats to the arguments of syft and cosign
+var sbomFormats = map[buildv1alpha1.SBOMFormat]sbomTool{
+	buildv1alpha1.SBOMFormatSPDX:      {syftOutput: "spdx-json", cosignType: "spdx"},
+	buildv1alpha1.SBOMFormatCycloneDX: {syftOutput: "cyclonedx-json", cosignType: "cyclonedx"},
+}
+
+// getSBOMFormat returns the format of the SBOM of the Build, SPDX by default
+func getSBOMFormat(sbom *buildv1alpha1.ImageSBOM) buildv1alpha1.SBOMFormat {
+	if sbom.Format == "" {
+		return buildv1alpha1.SBOMFormatSPDX
+	}
+	return sbom.Format
+}
+
+// sbomGenerateStep returns a Task step that writes the SBOM of the digest of the pushed output
+// image, or of the source in the context directory, with syft
+func sbomGenerateStep(sbom *buildv1alpha1.ImageSBOM, syftImage string) (*v1beta1.Step, error) {
+	tool, ok := sbomFormats[getSBOMFormat(sbom)]
+	if !ok {
+		return nil, fmt.Errorf("the SBOM format %q is not supported", sbom.Format)
+	}
+
+	var script string
+	switch sbom.Scan {
+	case "", buildv1alpha1.SBOMScanImage:
+		script = outputImageScript + `exec /syft packages "registry:$IMAGE"`
+	case buildv1alpha1.SBOMScanSource:
+		script = fmt.Sprintf(`exec /syft packages "dir:$(inputs.params.%s)"`, inputParamContextDir)
+	default:
+		return nil, fmt.Errorf("the SBOM scan %q is not supported", sbom.Scan)
+	}
+
+	container := corev1.Container{
+		Name:       sbomGenerateStepName,
+		Image:      syftImage,
+		WorkingDir: workspaceDir,
+		Env: []corev1.EnvVar{
+			{Name: "DOCKER_CONFIG", Value: "/tekton/home/.docker"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: sbomVolumeName, MountPath: sbomDir},
+		},
+		Command: []string{"sh"},
+		Args: []string{
+			"-c",
+			fmt.Sprintf("%s --output=%s --file=%s", script, tool.syftOutput, sbomFile),
+		},
+	}
+	return &v1beta1.Step{Container: container}, nil
+}
+
+// sbomAttachStep returns a Task step that attaches the SBOM to the digest of the pushed output
+// image with cosign, cosign pushes the SBOM next to the image in its repository
+func sbomAttachStep(sbom *buildv1alpha1.ImageSBOM, cosignImage string) v1beta1.Step {
+	container := corev1.Container{
+		Name:  sbomAttachStepName,
+		Image: cosignImage,
+		Env: []corev1.EnvVar{
+			{Name: "DOCKER_CONFIG", Value: "/tekton/home/.docker"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: sbomVolumeName, MountPath: sbomDir, ReadOnly: true},
+		},
+		Command: []string{"sh"},
+		Args: []string{
+			"-c",
+			outputImageScript + fmt.Sprintf(`exec cosign attach sbom --sbom=%s --type=%s "$IMAGE"`, sbomFile, sbomFormats[getSBOMFormat(sbom)].cosignType),
+		},
+	}
+	return v1beta1.Step{Container: container}
+}
+
+// AmendTaskSpecWithSBOM adds steps to Tekton's Task that generate the SBOM of the output image
+// after the other steps pushed it, and attach the SBOM to the image.
+func AmendTaskSpecWithSBOM(cfg *config.Config, spec *v1beta1.TaskSpec, b *buildv1alpha1.Build) error {
+	step, err := sbomGenerateStep(b.Spec.Output.SBOM, cfg.SyftContainerImage)
+	if err != nil {
+		return err
+	}
+	spec.Steps = append(spec.Steps, *step, sbomAttachStep(b.Spec.Output.SBOM, cfg.CosignContainerImage))
+
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: sbomVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	return nil
+}
+
+// sbomReference returns the reference of the SBOM that cosign attaches to the digest of the
+// image, it is the tag sha256-<hash>.sbom in the repository of the image
+func sbomReference(image string, digest string) string {
+	if digest == "" {
+		return ""
+	}
+	return imageWithTag(image, strings.Replace(digest, ":", "-", 1)+".sbom")
+}
+
+// updateBuildRunSBOM records the reference of the SBOM of the output image in the status of the
+// BuildRun, if the Build generates one. A Build with platforms cannot generate SBOMs.
+func updateBuildRunSBOM(buildRun *buildv1alpha1.BuildRun) {
+	if buildRun.Status.BuildSpec == nil || buildRun.Status.BuildSpec.Output.SBOM == nil {
+		return
+	}
+
+	if buildRun.Status.Output != nil {
+		buildRun.Status.Output.SBOM = sbomReference(buildRun.Status.Output.Image, buildRun.Status.Output.Digest)
+	}
+}
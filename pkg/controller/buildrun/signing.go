@@ -0,0 +1,99 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// signingStepName is the name of the step that signs the output image
+	signingStepName = "sign-image"
+
+	// signingVolumeName is the name of the volume with the secret of the signing key
+	signingVolumeName = "shp-signing-key"
+
+	// signingKeyDir is the directory in which the secret of the signing key is mounted
+	signingKeyDir = "/workspace/signing"
+)
+
+// signingStep returns a Task step that signs the digest of the pushed output image with cosign,
+// cosign pushes the signature next to the image in its repository.
+func signingStep(cosignImage string, secretName string) v1beta1.Step {
+	container := corev1.Container{
+		Name:  signingStepName,
+		Image: cosignImage,
+		Env: []corev1.EnvVar{
+			{Name: "DOCKER_CONFIG", Value: "/tekton/home/.docker"},
+			{
+				Name: "COSIGN_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+						Key:                  buildv1alpha1.SigningSecretPasswordKey,
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: signingVolumeName, MountPath: signingKeyDir, ReadOnly: true},
+		},
+		Command: []string{"sh"},
+		Args: []string{
+			"-c",
+			outputImageScript + fmt.Sprintf(`exec cosign sign --key=%s "$IMAGE"`, path.Join(signingKeyDir, buildv1alpha1.SigningSecretKey)),
+		},
+	}
+	return v1beta1.Step{Container: container}
+}
+
+// AmendTaskSpecWithSigning adds a step to Tekton's Task that signs the output image after the
+// other steps pushed it, and the volume with the signing key of the Build.
+func AmendTaskSpecWithSigning(cfg *config.Config, spec *v1beta1.TaskSpec, b *buildv1alpha1.Build) {
+	secretName := b.Spec.Output.Signing.SecretRef.Name
+
+	spec.Steps = append(spec.Steps, signingStep(cfg.CosignContainerImage, secretName))
+
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: signingVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []corev1.KeyToPath{
+					{Key: buildv1alpha1.SigningSecretKey, Path: buildv1alpha1.SigningSecretKey},
+				},
+			},
+		},
+	})
+}
+
+// signatureReference returns the reference of the signature that cosign pushes for the digest
+// of the image, it is the tag sha256-<hash>.sig in the repository of the image
+func signatureReference(image string, digest string) string {
+	if digest == "" {
+		return ""
+	}
+	return imageWithTag(image, strings.Replace(digest, ":", "-", 1)+".sig")
+}
+
+// updateBuildRunSignature records the reference of the signature of the output image in the
+// status of the BuildRun, if the Build signs its output. A Build with platforms cannot sign its
+// output.
+func updateBuildRunSignature(buildRun *buildv1alpha1.BuildRun) {
+	if buildRun.Status.BuildSpec == nil || buildRun.Status.BuildSpec.Output.Signing == nil {
+		return
+	}
+
+	if buildRun.Status.Output != nil {
+		buildRun.Status.Output.Signature = signatureReference(buildRun.Status.Output.Image, buildRun.Status.Output.Digest)
+	}
+}
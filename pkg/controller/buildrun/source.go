@@ -0,0 +1,194 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// sourceStepName is the name of the step that fetches the source and records the details of the commit
+	sourceStepName = "source-fetch"
+
+	// sourceWorkspaceName is the name of the workspace into which the source is fetched
+	sourceWorkspaceName = "source"
+
+	// defaultSourceName is the name of the single source of a Build in the BuildRun status
+	defaultSourceName = "default"
+
+	// defaultSourceRevision is the revision that is fetched if the Build does not define one
+	defaultSourceRevision = "master"
+
+	resultSourceCommitSha       = "shp-source-commit-sha"
+	resultSourceCommitAuthor    = "shp-source-commit-author"
+	resultSourceCommitTimestamp = "shp-source-commit-timestamp"
+)
+
+// sourceScript fetches the revision of the repository into the source workspace, with a fallback to a
+// complete fetch for revisions that cannot be fetched directly, like abbreviated commit SHAs, and
+// writes the details of the commit to the Task results
+var sourceScript = strings.Join([]string{
+	"set -e",
+	fmt.Sprintf("cd %s", workspaceDir),
+	"git init -q",
+	`git remote add origin "${SOURCE_URL}"`,
+	`if git fetch -q --depth=1 origin "${SOURCE_REVISION}"; then`,
+	"  git checkout -q FETCH_HEAD",
+	"else",
+	"  git fetch -q origin",
+	`  git checkout -q "${SOURCE_REVISION}"`,
+	"fi",
+	"git submodule -q update --init --recursive --depth=1",
+	fmt.Sprintf("git rev-parse HEAD >$(results.%s.path)", resultSourceCommitSha),
+	fmt.Sprintf("git log -1 --format=%%an >$(results.%s.path)", resultSourceCommitAuthor),
+	fmt.Sprintf("git log -1 --format=%%cI >$(results.%s.path)", resultSourceCommitTimestamp),
+}, "\n")
+
+// sourceWorkspace returns the declaration of the workspace into which the source is fetched,
+// it is mounted at the directory where the strategy steps expect the source
+func sourceWorkspace() v1beta1.WorkspaceDeclaration {
+	return v1beta1.WorkspaceDeclaration{
+		Name:        sourceWorkspaceName,
+		Description: "The directory into which the source is fetched",
+		MountPath:   workspaceDir,
+	}
+}
+
+// sourceResults returns the Task results that the source step writes
+func sourceResults() []v1beta1.TaskResult {
+	return []v1beta1.TaskResult{
+		{
+			Name:        resultSourceCommitSha,
+			Description: "The SHA of the commit that was checked out",
+		},
+		{
+			Name:        resultSourceCommitAuthor,
+			Description: "The author of the commit that was checked out",
+		},
+		{
+			Name:        resultSourceCommitTimestamp,
+			Description: "The time in RFC3339 format when the commit that was checked out was created",
+		},
+	}
+}
+
+// sourceStep returns a Task step that fetches the source into the source workspace. The URL and
+// revision are passed as environment variables, so that they are never interpreted by the shell.
+// Credentials of the service account are available to git through the home directory of the step.
+func sourceStep(cfg *config.Config, build *buildv1alpha1.Build) v1beta1.Step {
+	env := []corev1.EnvVar{
+		{Name: "SOURCE_URL", Value: fmt.Sprintf("$(inputs.params.%s)", inputParamSourceURL)},
+		{Name: "SOURCE_REVISION", Value: fmt.Sprintf("$(inputs.params.%s)", inputParamSourceRevision)},
+	}
+	env = append(env, sourceProxyEnv(cfg.SourceProxy, build.Spec.Source)...)
+
+	return v1beta1.Step{
+		Container: corev1.Container{
+			Name:    sourceStepName,
+			Image:   cfg.GitContainerImage,
+			Command: []string{"/bin/sh"},
+			Args:    []string{"-c", sourceScript},
+			Env:     env,
+		},
+	}
+}
+
+// sourceProxyEnv returns the proxy environment variables for the source step. Every proxy
+// setting of the source overrides the default of the controller configuration. The variables
+// are set in upper and lower case, because tools like curl only honor the lower case ones.
+func sourceProxyEnv(defaults config.ProxyConfig, source buildv1alpha1.GitSource) []corev1.EnvVar {
+	settings := []struct {
+		name  string
+		value string
+	}{
+		{"HTTP_PROXY", defaults.HTTPProxy},
+		{"HTTPS_PROXY", defaults.HTTPSProxy},
+		{"NO_PROXY", defaults.NoProxy},
+	}
+	if source.HTTPProxy != "" {
+		settings[0].value = source.HTTPProxy
+	}
+	if source.HTTPSProxy != "" {
+		settings[1].value = source.HTTPSProxy
+	}
+	if source.NoProxy != "" {
+		settings[2].value = source.NoProxy
+	}
+
+	var env []corev1.EnvVar
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+		env = append(env,
+			corev1.EnvVar{Name: setting.name, Value: setting.value},
+			corev1.EnvVar{Name: strings.ToLower(setting.name), Value: setting.value},
+		)
+	}
+	return env
+}
+
+// getSourceRevision returns the revision of the source that the Build defines, or the default revision
+func getSourceRevision(build *buildv1alpha1.Build) string {
+	if build.Spec.Source.Revision != nil {
+		return *build.Spec.Source.Revision
+	}
+	return defaultSourceRevision
+}
+
+// updateBuildRunSources records the details of the commit that the TaskRun checked out in the
+// status of the BuildRun, using the results of the source step
+func updateBuildRunSources(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun) {
+	git := &buildv1alpha1.GitSourceResult{}
+
+	for _, result := range taskRun.Status.TaskRunResults {
+		value := strings.TrimSpace(result.Value)
+		switch result.Name {
+		case resultSourceCommitSha:
+			git.CommitSha = value
+		case resultSourceCommitAuthor:
+			git.CommitAuthor = value
+		case resultSourceCommitTimestamp:
+			timestamp, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				ctxlog.Info(ctx, "ignoring invalid commit timestamp result", namespace, buildRun.Namespace, name, buildRun.Name, "value", value)
+				continue
+			}
+			git.CommitTimestamp = &metav1.Time{Time: timestamp}
+		}
+	}
+
+	if git.CommitSha == "" && git.CommitAuthor == "" && git.CommitTimestamp == nil {
+		return
+	}
+
+	// the requested revision is a branch or a tag, unless it is (a prefix of) the commit SHA
+	if buildRun.Status.BuildSpec != nil {
+		revision := defaultSourceRevision
+		if buildRun.Status.BuildSpec.Source.Revision != nil {
+			revision = *buildRun.Status.BuildSpec.Source.Revision
+		}
+		if git.CommitSha == "" || !strings.HasPrefix(git.CommitSha, revision) {
+			git.Branch = revision
+		}
+	}
+
+	buildRun.Status.Sources = []buildv1alpha1.SourceResult{
+		{
+			Name: defaultSourceName,
+			Git:  git,
+		},
+	}
+}
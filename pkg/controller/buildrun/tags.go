@@ -0,0 +1,115 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/utils"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// tagTimestampFormat is the format of the timestamp in tag templates, tags must not contain colons
+const tagTimestampFormat = "20060102150405"
+
+// shortSHALength is the length of the abbreviated commit SHA in tag templates
+const shortSHALength = 7
+
+// tagOutputImage adds the further tags of the output of the Build to the image that the TaskRun
+// pushed, and records the references of all tags in the output of the BuildRun
+func (r *ReconcileBuildRun) tagOutputImage(ctx context.Context, buildRun *buildv1alpha1.BuildRun) error {
+	if buildRun.Status.BuildSpec == nil || len(buildRun.Status.BuildSpec.Output.Tags) == 0 || buildRun.Status.Output == nil {
+		return nil
+	}
+	output := buildRun.Status.BuildSpec.Output
+
+	tags, err := renderTags(buildRun, output.Tags)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := r.getRegistryCredentials(ctx, buildRun.Namespace, output)
+	if err != nil {
+		return err
+	}
+
+	// the digest is resolved from the tag that the build strategy pushed, if the strategy did not report it
+	digest := buildRun.Status.Output.Digest
+	if digest == "" {
+		if digest, err = r.registry.Resolve(ctx, output.ImageURL, credentials); err != nil {
+			return err
+		}
+	}
+
+	ctxlog.Info(ctx, "adding tags to the output image", namespace, buildRun.Namespace, name, buildRun.Name, "image", output.ImageURL, "tags", strings.Join(tags, ","))
+	if err := r.registry.Tag(ctx, output.ImageURL, digest, tags, credentials); err != nil {
+		return err
+	}
+
+	images := []string{output.ImageURL}
+	for _, tag := range tags {
+		if image := imageWithTag(output.ImageURL, tag); image != output.ImageURL {
+			images = append(images, image)
+		}
+	}
+	buildRun.Status.Output.Digest = digest
+	buildRun.Status.Output.Images = images
+	return nil
+}
+
+// renderTags executes the tag templates with the data of the BuildRun. A template that results
+// in an empty tag, for example because the BuildRun has no git revision, is an error.
+func renderTags(buildRun *buildv1alpha1.BuildRun, templates []string) ([]string, error) {
+	data := utils.TagTemplateData{
+		BuildRun:  utils.TagTemplateObject{Name: buildRun.Name, Namespace: buildRun.Namespace},
+		Timestamp: buildRun.CreationTimestamp.UTC().Format(tagTimestampFormat),
+	}
+	if buildRun.Spec.BuildRef != nil {
+		data.Build = utils.TagTemplateObject{Name: buildRun.Spec.BuildRef.Name, Namespace: buildRun.Namespace}
+	}
+	for _, source := range buildRun.Status.Sources {
+		if source.Git != nil {
+			data.Revision = utils.TagTemplateRevision{SHA: source.Git.CommitSha, ShortSHA: source.Git.CommitSha, Branch: source.Git.Branch}
+			if len(data.Revision.ShortSHA) > shortSHALength {
+				data.Revision.ShortSHA = data.Revision.ShortSHA[:shortSHALength]
+			}
+			break
+		}
+	}
+	return utils.RenderTags(templates, data)
+}
+
+// getRegistryCredentials returns the credentials in the secret of the image, if it has one
+func (r *ReconcileBuildRun) getRegistryCredentials(ctx context.Context, ns string, image buildv1alpha1.Image) (*registry.Credentials, error) {
+	if image.SecretRef == nil || image.SecretRef.Name == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: image.SecretRef.Name}, secret); err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, nil
+	}
+	return registry.CredentialsFromDockerConfig(data, image.ImageURL)
+}
+
+// imageWithTag replaces the tag or digest of the image with the tag
+func imageWithTag(image string, tag string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + ":" + tag
+}
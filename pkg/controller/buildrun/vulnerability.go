@@ -0,0 +1,189 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// vulnerabilityScanStepName is the name of the step that scans the output image
+	vulnerabilityScanStepName = "scan-image"
+
+	// resultVulnerabilities is the result in which the scan step reports the vulnerability counts
+	resultVulnerabilities = "shp-vulnerabilities"
+
+	// vulnerabilityPolicyViolationExitCode is the exit code of the scan step if the image has
+	// vulnerabilities of the severity of the policy or a higher one, other exit codes are errors
+	vulnerabilityPolicyViolationExitCode = 3
+
+	// vulnerabilityReport is the path of the report of trivy in the scan step
+	vulnerabilityReport = "/tmp/vulnerabilities.json"
+)
+
+// vulnerabilityScanScript scans the image in IMAGE with the local database of trivy, writes the
+// counts of the vulnerabilities by severity as result, and fails if the policy is violated. The
+// arguments are the options of trivy, the severities that violate the policy, and the paths.
+const vulnerabilityScanScript = `trivy image --skip-update --offline-scan --no-progress --format json --output %[3]s %[1]s"$IMAGE"
+count() { grep -c "\"Severity\": \"$1\"" %[3]s || true; }
+CRITICAL=$(count CRITICAL) HIGH=$(count HIGH) MEDIUM=$(count MEDIUM) LOW=$(count LOW) UNKNOWN=$(count UNKNOWN)
+printf '{"critical":%%d,"high":%%d,"medium":%%d,"low":%%d,"unknown":%%d}' "$CRITICAL" "$HIGH" "$MEDIUM" "$LOW" "$UNKNOWN" >"$(results.%[4]s.path)"
+if [ $((%[2]s)) -gt 0 ]; then
+  echo "the image has vulnerabilities that violate the vulnerability policy"
+  exit %[5]d
+fi
+`
+
+// vulnerabilitySeverities are the severities of a policy from the highest to the lowest, with
+// the variables of their counts in the scan script
+var vulnerabilitySeverities = []struct {
+	severity buildv1alpha1.VulnerabilitySeverity
+	variable string
+}{
+	{buildv1alpha1.VulnerabilitySeverityCritical, "CRITICAL"},
+	{buildv1alpha1.VulnerabilitySeverityHigh, "HIGH"},
+	{buildv1alpha1.VulnerabilitySeverityMedium, "MEDIUM"},
+	{buildv1alpha1.VulnerabilitySeverityLow, "LOW"},
+}
+
+// getVulnerabilityPolicy returns the vulnerability policy of the Build, or of its strategy if
+// the Build has none
+func getVulnerabilityPolicy(build *buildv1alpha1.Build, strategy buildv1alpha1.BuilderStrategy) *buildv1alpha1.VulnerabilityPolicy {
+	if build.Spec.VulnerabilityPolicy != nil {
+		return build.Spec.VulnerabilityPolicy
+	}
+	return strategy.GetVulnerabilityPolicy()
+}
+
+// vulnerabilityScanStep returns a Task step that scans the digest of the pushed output image with
+// trivy
+func vulnerabilityScanStep(policy *buildv1alpha1.VulnerabilityPolicy, trivyImage string) (*v1beta1.Step, error) {
+	// the counts of the severity of the policy and all higher severities violate the policy
+	var violations []string
+	for _, s := range vulnerabilitySeverities {
+		violations = append(violations, s.variable)
+		if s.severity == policy.Severity {
+			break
+		}
+		if s.severity == buildv1alpha1.VulnerabilitySeverityLow {
+			return nil, fmt.Errorf("the vulnerability severity %q is not supported", policy.Severity)
+		}
+	}
+
+	var options string
+	if policy.IgnoreUnfixed {
+		options += "--ignore-unfixed "
+	}
+
+	container := corev1.Container{
+		Name:  vulnerabilityScanStepName,
+		Image: trivyImage,
+		Env: []corev1.EnvVar{
+			{Name: "DOCKER_CONFIG", Value: "/tekton/home/.docker"},
+		},
+		Command: []string{"/bin/sh"},
+		Args: []string{
+			"-c",
+			"set -e\n" + outputImageScript + fmt.Sprintf(vulnerabilityScanScript, options, strings.Join(violations, " + "), vulnerabilityReport, resultVulnerabilities, vulnerabilityPolicyViolationExitCode),
+		},
+	}
+	return &v1beta1.Step{Container: container}, nil
+}
+
+// AmendTaskSpecWithVulnerabilityScan adds a step to Tekton's Task that scans the output image
+// for vulnerabilities after the other steps pushed it, and the result of the scan.
+func AmendTaskSpecWithVulnerabilityScan(cfg *config.Config, spec *v1beta1.TaskSpec, policy *buildv1alpha1.VulnerabilityPolicy) error {
+	if cfg.TrivyContainerImage == "" {
+		return errors.New("the vulnerability policy cannot be enforced, because no container image with a vulnerability database is configured")
+	}
+
+	step, err := vulnerabilityScanStep(policy, cfg.TrivyContainerImage)
+	if err != nil {
+		return err
+	}
+	spec.Steps = append(spec.Steps, *step)
+
+	spec.Results = append(spec.Results, v1beta1.TaskResult{
+		Name:        resultVulnerabilities,
+		Description: "The number of vulnerabilities of the image that was pushed by severity",
+	})
+	return nil
+}
+
+// isVulnerabilityPolicyViolation returns true if the TaskRun failed because its scan step found
+// vulnerabilities that violate the vulnerability policy
+func isVulnerabilityPolicyViolation(taskRun *v1beta1.TaskRun) bool {
+	for _, step := range taskRun.Status.Steps {
+		if step.Name == vulnerabilityScanStepName && step.Terminated != nil {
+			return step.Terminated.ExitCode == vulnerabilityPolicyViolationExitCode
+		}
+	}
+	return false
+}
+
+// getVulnerabilitySummary returns the vulnerability counts that the scan step reported in the
+// results of the TaskRun, if any
+func getVulnerabilitySummary(ctx context.Context, taskRun *v1beta1.TaskRun) *buildv1alpha1.VulnerabilitySummary {
+	for _, result := range taskRun.Status.TaskRunResults {
+		if result.Name != resultVulnerabilities {
+			continue
+		}
+
+		summary := &buildv1alpha1.VulnerabilitySummary{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(result.Value)), summary); err != nil {
+			ctxlog.Info(ctx, "ignoring invalid vulnerabilities result", namespace, taskRun.Namespace, name, taskRun.Name, "value", result.Value)
+			return nil
+		}
+		return summary
+	}
+	return nil
+}
+
+// updateBuildRunVulnerabilities records the sum of the vulnerability counts of the TaskRuns in
+// the status of the BuildRun, if they scanned their images
+func updateBuildRunVulnerabilities(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRuns ...*v1beta1.TaskRun) {
+	var total *buildv1alpha1.VulnerabilitySummary
+	for _, taskRun := range taskRuns {
+		summary := getVulnerabilitySummary(ctx, taskRun)
+		if summary == nil {
+			continue
+		}
+		if total == nil {
+			total = &buildv1alpha1.VulnerabilitySummary{}
+		}
+		total.Critical += summary.Critical
+		total.High += summary.High
+		total.Medium += summary.Medium
+		total.Low += summary.Low
+		total.Unknown += summary.Unknown
+	}
+	buildRun.Status.Vulnerabilities = total
+}
+
+// getTaskRunReasonAndMessage maps the Succeeded condition of a TaskRun to the reason and the
+// message of the BuildRun, a TaskRun that failed because of the vulnerability policy has the
+// VulnerabilityPolicyViolation reason
+func getTaskRunReasonAndMessage(ctx context.Context, taskRun *v1beta1.TaskRun, trCondition *apis.Condition) (string, string) {
+	if trCondition.Status != corev1.ConditionFalse || !isVulnerabilityPolicyViolation(taskRun) {
+		return getSucceededConditionReasonAndMessage(trCondition)
+	}
+
+	message := "the image has vulnerabilities that violate the vulnerability policy"
+	if summary := getVulnerabilitySummary(ctx, taskRun); summary != nil {
+		message = fmt.Sprintf("%s: %d critical, %d high, %d medium, %d low, %d unknown", message, summary.Critical, summary.High, summary.Medium, summary.Low, summary.Unknown)
+	}
+	return buildv1alpha1.BuildRunReasonVulnerabilityPolicyViolation, message
+}
@@ -0,0 +1,219 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildschedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/cron"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	namespace string = "namespace"
+	name      string = "name"
+
+	// TriggerName is the value of the trigger annotation of the BuildRuns that the schedule creates
+	TriggerName = "schedule"
+
+	// tolerance is how late a BuildRun can be created after the time of the schedule without
+	// the time being considered missed
+	tolerance = 2 * time.Minute
+
+	// maxScheduleTimes is the maximum number of times of the schedule that latestTime iterates
+	// over in a window
+	maxScheduleTimes = 10000
+
+	// maxLookback is the longest window before now in which latestTime searches on its own,
+	// longer windows start at the time since which it searches
+	maxLookback = 100 * 365 * 24 * time.Hour
+)
+
+// Add creates a new BuildSchedule Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContext(ctx, "buildschedule-controller")
+	return add(mgr, NewReconciler(ctx, c, mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(ctx context.Context, c *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileBuildSchedule{
+		ctx:    ctx,
+		config: c,
+		client: mgr.GetClient(),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("buildschedule-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// The controller requeues every Build with a schedule until its next time, so that only
+	// changes of the spec need to be watched. At startup, the create events of all Builds
+	// catch up with the times that were missed while the controller was not running.
+	pred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	return c.Watch(&source.Kind{Type: &build.Build{}}, &handler.EnqueueRequestForObject{}, pred)
+}
+
+// blank assignment to verify that ReconcileBuildSchedule implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileBuildSchedule{}
+
+// ReconcileBuildSchedule creates the BuildRuns of the schedule of a Build
+type ReconcileBuildSchedule struct {
+	ctx    context.Context
+	config *config.Config
+	client client.Client
+}
+
+// Reconcile creates a BuildRun if a time of the schedule of the Build has come, updates the
+// schedule times in the status of the Build, and requeues the Build until its next time
+func (r *ReconcileBuildSchedule) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Debug(ctx, "start reconciling Build schedule", namespace, request.Namespace, name, request.Name)
+
+	b := &build.Build{}
+	if err := r.client.Get(ctx, request.NamespacedName, b); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if b.Spec.Schedule == nil {
+		if b.Status.NextScheduleTime == nil {
+			return reconcile.Result{}, nil
+		}
+		b.Status.NextScheduleTime = nil
+		return reconcile.Result{}, r.client.Status().Update(ctx, b)
+	}
+
+	schedule, err := cron.Parse(b.Spec.Schedule.Cron)
+	if err != nil {
+		// the Build controller reports the invalid expression in the ScheduleValid condition
+		ctxlog.Info(ctx, "ignoring the invalid schedule of the Build", namespace, b.Namespace, name, b.Name, "error", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	now := time.Now().UTC()
+
+	// the times since the last BuildRun of the schedule, or since the Build was created
+	since := b.CreationTimestamp.Time
+	if b.Status.LastScheduleTime != nil {
+		since = b.Status.LastScheduleTime.Time
+	}
+	latest := latestTime(schedule, since.UTC(), now)
+
+	if !latest.IsZero() {
+		onTime := now.Sub(latest) <= tolerance
+		switch {
+		case onTime || b.Spec.Schedule.CatchUpPolicy == build.ScheduleCatchUpRunOnce:
+			if err := r.createBuildRun(ctx, b, latest); err != nil {
+				return reconcile.Result{}, err
+			}
+
+		default:
+			ctxlog.Info(ctx, "skipping missed times of the schedule", namespace, b.Namespace, name, b.Name, "since", since.String(), "latest", latest.String())
+		}
+
+		// the skipped times are handled as well, so that they are not counted again
+		b.Status.LastScheduleTime = &metav1.Time{Time: latest}
+	}
+
+	next := schedule.Next(now)
+	if next.IsZero() {
+		b.Status.NextScheduleTime = nil
+	} else {
+		b.Status.NextScheduleTime = &metav1.Time{Time: next}
+	}
+
+	if err := r.client.Status().Update(ctx, b); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	ctxlog.Debug(ctx, "finishing reconciling Build schedule", namespace, request.Namespace, name, request.Name)
+	if next.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// createBuildRun creates the BuildRun for a time of the schedule. Its name is derived from the
+// time, so that a retry after a failed status update does not create a second BuildRun.
+func (r *ReconcileBuildSchedule) createBuildRun(ctx context.Context, b *build.Build, scheduled time.Time) error {
+	buildRun := &build.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", b.Name, scheduled.Unix()/60),
+			Namespace: b.Namespace,
+			Annotations: map[string]string{
+				build.AnnotationBuildRunTrigger: TriggerName,
+			},
+		},
+		Spec: build.BuildRunSpec{
+			BuildRef: &build.BuildRef{
+				Name: b.Name,
+			},
+		},
+	}
+
+	if err := r.client.Create(ctx, buildRun); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	ctxlog.Info(ctx, "created the BuildRun of the schedule", namespace, b.Namespace, name, b.Name, "buildrun", buildRun.Name, "time", scheduled.String())
+	return nil
+}
+
+// latestTime returns the latest time of the schedule after since and not after now. It searches
+// in windows before now that double in length, so that the missed times of a long outage are
+// not all iterated over, and iterates over at most maxScheduleTimes times in a window.
+func latestTime(schedule *cron.Schedule, since time.Time, now time.Time) time.Time {
+	for window := time.Hour; ; window *= 2 {
+		start := now.Add(-window)
+		if !start.After(since) || window > maxLookback {
+			start = since
+		}
+
+		var latest time.Time
+		t := schedule.Next(start)
+		for i := 0; i < maxScheduleTimes && !t.IsZero() && !t.After(now); i++ {
+			latest = t
+			t = schedule.Next(t)
+		}
+
+		if !latest.IsZero() || start.Equal(since) {
+			return latest
+		}
+	}
+}
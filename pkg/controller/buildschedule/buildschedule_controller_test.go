@@ -0,0 +1,180 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package buildschedule_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/buildschedule"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile BuildSchedule", func() {
+	var (
+		manager      *fakes.FakeManager
+		client       *fakes.FakeClient
+		statusWriter *fakes.FakeStatusWriter
+		reconciler   reconcile.Reconciler
+		request      reconcile.Request
+		buildSample  *build.Build
+		buildRuns    []*build.BuildRun
+		updated      *build.Build
+	)
+
+	BeforeEach(func() {
+		buildRuns, updated = nil, nil
+		buildSample = &build.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "nightly",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().AddDate(-3, 0, 0)),
+			},
+		}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "nightly", Namespace: "default"}}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			if b, ok := object.(*build.Build); ok && nn.Name == buildSample.Name {
+				buildSample.DeepCopyInto(b)
+				return nil
+			}
+			return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+			buildRuns = append(buildRuns, object.(*build.BuildRun))
+			return nil
+		})
+
+		statusWriter = &fakes.FakeStatusWriter{}
+		statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+			updated = object.(*build.Build)
+			return nil
+		})
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+
+		manager = &fakes.FakeManager{}
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		testCtx := ctxlog.NewContext(context.TODO(), "fake-logger")
+		reconciler = buildschedule.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
+	})
+
+	Context("when a time of the schedule has come", func() {
+		BeforeEach(func() {
+			buildSample.CreationTimestamp = metav1.NewTime(time.Now().Add(-90 * time.Second))
+			buildSample.Spec.Schedule = &build.Schedule{Cron: "* * * * *"}
+		})
+
+		It("creates a BuildRun and requeues the Build until the next time", func() {
+			result, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(buildRuns).To(HaveLen(1))
+			buildRun := buildRuns[0]
+			Expect(buildRun.Namespace).To(Equal("default"))
+			Expect(buildRun.Spec.BuildRef.Name).To(Equal("nightly"))
+			Expect(buildRun.Annotations).To(HaveKeyWithValue(build.AnnotationBuildRunTrigger, "schedule"))
+
+			Expect(updated).ToNot(BeNil())
+			Expect(updated.Status.LastScheduleTime).ToNot(BeNil())
+			Expect(buildRun.Name).To(Equal(fmt.Sprintf("nightly-%d", updated.Status.LastScheduleTime.Unix()/60)))
+			Expect(updated.Status.NextScheduleTime).ToNot(BeNil())
+			Expect(updated.Status.NextScheduleTime.Time).To(BeTemporally(">", time.Now()))
+
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+		})
+
+		It("accepts that the BuildRun of the time already exists", func() {
+			client.CreateReturns(k8serrors.NewAlreadyExists(schema.GroupResource{}, "nightly"))
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Status.LastScheduleTime).ToNot(BeNil())
+		})
+	})
+
+	Context("when times of the schedule were missed", func() {
+		BeforeEach(func() {
+			buildSample.Spec.Schedule = &build.Schedule{Cron: "@yearly"}
+			buildSample.Status.LastScheduleTime = &metav1.Time{Time: time.Date(time.Now().Year()-3, 1, 1, 0, 0, 0, 0, time.UTC)}
+		})
+
+		It("skips them by default", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(buildRuns).To(BeEmpty())
+			Expect(updated.Status.LastScheduleTime.Time).To(Equal(time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(updated.Status.NextScheduleTime.Time).To(Equal(time.Date(time.Now().Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("skips the many missed times of a long outage in one go", func() {
+			buildSample.Spec.Schedule = &build.Schedule{Cron: "* 0 1 1 *"}
+			buildSample.Status.LastScheduleTime = &metav1.Time{Time: time.Date(time.Now().Year()-20, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(buildRuns).To(BeEmpty())
+			Expect(updated.Status.LastScheduleTime.Time).To(Equal(time.Date(time.Now().Year(), 1, 1, 0, 59, 0, 0, time.UTC)))
+		})
+
+		It("creates a single BuildRun for them with the RunOnce policy", func() {
+			buildSample.Spec.Schedule.CatchUpPolicy = build.ScheduleCatchUpRunOnce
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(buildRuns).To(HaveLen(1))
+			Expect(updated.Status.LastScheduleTime.Time).To(Equal(time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("when the Build has no schedule", func() {
+		It("removes the next time of a previous schedule", func() {
+			buildSample.Status.NextScheduleTime = &metav1.Time{Time: time.Now().Add(time.Hour)}
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buildRuns).To(BeEmpty())
+			Expect(updated.Status.NextScheduleTime).To(BeNil())
+		})
+
+		It("does not update a Build without schedule times", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the schedule is invalid", func() {
+		It("does not create BuildRuns", func() {
+			buildSample.Spec.Schedule = &build.Schedule{Cron: "every day"}
+
+			result, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(buildRuns).To(BeEmpty())
+		})
+	})
+})
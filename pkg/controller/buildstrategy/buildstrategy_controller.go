@@ -9,7 +9,10 @@ import (
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/utils"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -19,6 +22,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// succeedStatus default status for the BuildStrategy CRD
+const succeedStatus string = "Succeeded"
+
 // Add creates a new BuildStrategy Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
@@ -75,5 +81,31 @@ func (r *ReconcileBuildStrategy) Reconcile(request reconcile.Request) (reconcile
 	defer cancel()
 
 	ctxlog.Info(ctx, "reconciling BuildStrategy", "namespace", request.Namespace, "name", request.Name)
-	return reconcile.Result{}, nil
+
+	buildStrategy := &buildv1alpha1.BuildStrategy{}
+	if err := r.client.Get(ctx, request.NamespacedName, buildStrategy); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debug(ctx, "finish reconciling BuildStrategy. BuildStrategy was not found", "namespace", request.Namespace, "name", request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Validate if the build steps only use known placeholders and declared parameters
+	status := buildv1alpha1.BuildStrategyStatus{
+		Registered: corev1.ConditionTrue,
+		Reason:     succeedStatus,
+	}
+	if err := utils.ValidateStrategyPlaceholders(buildStrategy); err != nil {
+		ctxlog.Info(ctx, "BuildStrategy is invalid", "namespace", request.Namespace, "name", request.Name, "reason", err.Error())
+		status.Registered = corev1.ConditionFalse
+		status.Reason = err.Error()
+	}
+
+	if buildStrategy.Status == status {
+		return reconcile.Result{}, nil
+	}
+
+	buildStrategy.Status = status
+	return reconcile.Result{}, r.client.Status().Update(ctx, buildStrategy)
 }
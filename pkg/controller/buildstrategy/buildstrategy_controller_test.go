@@ -9,11 +9,18 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	buildstrategyController "github.com/shipwright-io/build/pkg/controller/buildstrategy"
 	"github.com/shipwright-io/build/pkg/controller/fakes"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/test"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -23,6 +30,10 @@ var _ = Describe("Reconcile BuildStrategy", func() {
 		reconciler                   reconcile.Reconciler
 		request                      reconcile.Request
 		namespace, buildStrategyName string
+		buildStrategySample          *build.BuildStrategy
+		client                       *fakes.FakeClient
+		statusWriter                 *fakes.FakeStatusWriter
+		ctl                          test.Catalog
 	)
 
 	BeforeEach(func() {
@@ -32,6 +43,29 @@ var _ = Describe("Reconcile BuildStrategy", func() {
 		// Fake the manager and get a reconcile Request
 		manager = &fakes.FakeManager{}
 		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: buildStrategyName, Namespace: namespace}}
+
+		strategy, err := ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategySingleStep))
+		Expect(err).ToNot(HaveOccurred())
+		buildStrategySample = strategy
+
+		// Fake the client GET calls when reconciling,
+		// in order to get our BuildStrategy CRD instance
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+			switch object := object.(type) {
+			case *build.BuildStrategy:
+				if buildStrategySample == nil {
+					return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+				}
+				buildStrategySample.DeepCopyInto(object)
+			default:
+				return errors.NewNotFound(schema.GroupResource{}, "schema not found")
+			}
+			return nil
+		})
+		statusWriter = &fakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
 	})
 
 	JustBeforeEach(func() {
@@ -47,6 +81,63 @@ var _ = Describe("Reconcile BuildStrategy", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(reconcile.Result{}).To(Equal(result))
 			})
+
+			It("registers a BuildStrategy that only uses known placeholders", func() {
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					strategy, ok := object.(*build.BuildStrategy)
+					Expect(ok).To(BeTrue())
+					Expect(strategy.Status.Registered).To(Equal(corev1.ConditionTrue))
+					Expect(strategy.Status.Reason).To(Equal("Succeeded"))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("does not update the status when it did not change", func() {
+				buildStrategySample.Status.Registered = corev1.ConditionTrue
+				buildStrategySample.Status.Reason = "Succeeded"
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the BuildStrategy uses an unknown placeholder", func() {
+			BeforeEach(func() {
+				strategy, err := ctl.LoadBuildStrategyYAML([]byte(test.BuildahBuildStrategyWithUnknownPlaceholder))
+				Expect(err).ToNot(HaveOccurred())
+				buildStrategySample.Spec = strategy.Spec
+			})
+
+			It("does not register the BuildStrategy", func() {
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					strategy, ok := object.(*build.BuildStrategy)
+					Expect(ok).To(BeTrue())
+					Expect(strategy.Status.Registered).To(Equal(corev1.ConditionFalse))
+					Expect(strategy.Status.Reason).To(Equal("the build strategy references unknown placeholder \"$(build.outptu.image)\""))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the BuildStrategy does not exist", func() {
+			BeforeEach(func() {
+				buildStrategySample = nil
+			})
+
+			It("succeeds without updating a status", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
 		})
 	})
 })
@@ -9,7 +9,10 @@ import (
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/utils"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -19,6 +22,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// succeedStatus default status for the ClusterBuildStrategy CRD
+const succeedStatus string = "Succeeded"
+
 // Add creates a new ClusterBuildStrategy Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
@@ -75,5 +81,31 @@ func (r *ReconcileClusterBuildStrategy) Reconcile(request reconcile.Request) (re
 	defer cancel()
 
 	ctxlog.Info(ctx, "reconciling ClusterBuildStrategy", "name", request.Name)
-	return reconcile.Result{}, nil
+
+	clusterBuildStrategy := &buildv1alpha1.ClusterBuildStrategy{}
+	if err := r.client.Get(ctx, request.NamespacedName, clusterBuildStrategy); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debug(ctx, "finish reconciling ClusterBuildStrategy. ClusterBuildStrategy was not found", "name", request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Validate if the build steps only use known placeholders and declared parameters
+	status := buildv1alpha1.BuildStrategyStatus{
+		Registered: corev1.ConditionTrue,
+		Reason:     succeedStatus,
+	}
+	if err := utils.ValidateStrategyPlaceholders(clusterBuildStrategy); err != nil {
+		ctxlog.Info(ctx, "ClusterBuildStrategy is invalid", "name", request.Name, "reason", err.Error())
+		status.Registered = corev1.ConditionFalse
+		status.Reason = err.Error()
+	}
+
+	if clusterBuildStrategy.Status == status {
+		return reconcile.Result{}, nil
+	}
+
+	clusterBuildStrategy.Status = status
+	return reconcile.Result{}, r.client.Status().Update(ctx, clusterBuildStrategy)
 }
@@ -0,0 +1,68 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// GetBuildParameters returns the parameters defined in `.spec.parameters` of the build, or nil.
+func GetBuildParameters(b *buildv1alpha1.Build) []buildv1alpha1.Parameter {
+	if b.Spec.Parameters == nil {
+		return nil
+	}
+	return *b.Spec.Parameters
+}
+
+// ValidateParameters verifies the parameters set in a build against the parameter definitions of
+// a build strategy. Every parameter must be declared by the strategy, must match its declared type,
+// and every parameter without a default value must be set.
+func ValidateParameters(definitions []buildv1alpha1.ParameterDefinition, parameters []buildv1alpha1.Parameter) error {
+	declared := map[string]buildv1alpha1.ParameterDefinition{}
+	for _, definition := range definitions {
+		declared[definition.Name] = definition
+	}
+
+	var problems []string
+	defined := map[string]bool{}
+	for _, parameter := range parameters {
+		if defined[parameter.Name] {
+			problems = append(problems, fmt.Sprintf("parameter %q is set more than once", parameter.Name))
+			continue
+		}
+		defined[parameter.Name] = true
+
+		definition, ok := declared[parameter.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("parameter %q is not declared by the build strategy", parameter.Name))
+			continue
+		}
+
+		switch definition.GetType() {
+		case buildv1alpha1.ParameterTypeString:
+			if parameter.Values != nil {
+				problems = append(problems, fmt.Sprintf("parameter %q is of type string and cannot have values", parameter.Name))
+			}
+		case buildv1alpha1.ParameterTypeArray:
+			if parameter.Value != "" {
+				problems = append(problems, fmt.Sprintf("parameter %q is of type array and cannot have a value", parameter.Name))
+			}
+		}
+	}
+
+	for _, definition := range definitions {
+		if definition.IsRequired() && !defined[definition.Name] {
+			problems = append(problems, fmt.Sprintf("parameter %q is required by the build strategy but not set", definition.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, ", "))
+	}
+	return nil
+}
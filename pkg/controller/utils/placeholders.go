@@ -0,0 +1,80 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/placeholder"
+)
+
+// ValidateStrategyPlaceholders verifies that the build steps of the strategy only reference known
+// placeholders and declared parameters, that parameters of type array are only referenced as a
+// complete command or argument, which is where Tekton can expand them, and that every declared
+// parameter is referenced, so that a parameter set by a Build is never silently ignored
+func ValidateStrategyPlaceholders(strategy build.BuilderStrategy) error {
+	known := map[string]bool{}
+	for _, name := range placeholder.BuildPlaceholders {
+		known[name] = true
+	}
+
+	declared := map[string]build.ParameterDefinition{}
+	for _, parameter := range strategy.GetParameters() {
+		declared[parameter.Name] = parameter
+	}
+
+	resolver := placeholder.NewResolver(placeholder.Build, nil)
+
+	referenced := map[string]bool{}
+	validate := func(text string, standalone bool) error {
+		for _, reference := range resolver.References(text) {
+			if parameterName, ok := placeholder.ParameterName(reference.Name); ok {
+				parameter, ok := declared[parameterName]
+				if !ok {
+					return fmt.Errorf("the build strategy references parameter %q, which it does not declare", parameterName)
+				}
+				referenced[parameterName] = true
+				if parameter.GetType() == build.ParameterTypeArray && (!standalone || !reference.Complete) {
+					return fmt.Errorf("the build strategy references array parameter %q in %q, but array parameters can only be used as a complete command or argument", parameterName, text)
+				}
+				continue
+			}
+
+			if !known[reference.Name] {
+				return fmt.Errorf("the build strategy references unknown placeholder \"$(%s)\"", reference.Name)
+			}
+		}
+		return nil
+	}
+
+	for _, step := range strategy.GetBuildSteps() {
+		if err := validate(step.Image, false); err != nil {
+			return err
+		}
+		for _, command := range step.Command {
+			if err := validate(command, true); err != nil {
+				return err
+			}
+		}
+		for _, arg := range step.Args {
+			if err := validate(arg, true); err != nil {
+				return err
+			}
+		}
+		for _, env := range step.Env {
+			if err := validate(env.Value, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, parameter := range strategy.GetParameters() {
+		if !referenced[parameter.Name] {
+			return fmt.Errorf("the build strategy declares parameter %q, which no step references", parameter.Name)
+		}
+	}
+	return nil
+}
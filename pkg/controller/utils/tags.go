@@ -0,0 +1,92 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/registry"
+)
+
+// invalidTagCharacters matches the characters that are not allowed in tags
+var invalidTagCharacters = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// TagTemplateData is the data that tag templates like {{.Revision.ShortSHA}} can use
+type TagTemplateData struct {
+	Build     TagTemplateObject
+	BuildRun  TagTemplateObject
+	Revision  TagTemplateRevision
+	Timestamp string
+}
+
+// TagTemplateObject is the name of an object in tag templates
+type TagTemplateObject struct {
+	Name      string
+	Namespace string
+}
+
+// TagTemplateRevision is the git commit that the BuildRun built, in tag templates
+type TagTemplateRevision struct {
+	SHA      string
+	ShortSHA string
+	Branch   string
+}
+
+// RenderTags executes the tag templates with the data. The characters of the branch that are not
+// allowed in tags are replaced with a dash, for example feature/x becomes feature-x. A template
+// that results in an empty tag, for example because the BuildRun has no git revision, is an
+// error. Duplicate tags are only returned once.
+func RenderTags(templates []string, data TagTemplateData) ([]string, error) {
+	data.Revision.Branch = invalidTagCharacters.ReplaceAllString(data.Revision.Branch, "-")
+
+	var tags []string
+	seen := map[string]bool{}
+	for _, text := range templates {
+		tmpl, err := template.New("tag").Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag template %q: %v", text, err)
+		}
+
+		var tag bytes.Buffer
+		if err := tmpl.Execute(&tag, data); err != nil {
+			return nil, fmt.Errorf("failed to execute the tag template %q: %v", text, err)
+		}
+		if tag.Len() == 0 {
+			return nil, fmt.Errorf("the tag template %q results in an empty tag", text)
+		}
+
+		if !seen[tag.String()] {
+			seen[tag.String()] = true
+			tags = append(tags, tag.String())
+		}
+	}
+	return tags, nil
+}
+
+// ValidateTagTemplates verifies the tag templates in `.spec.output.tags` of the build, by
+// executing them with sample data of a BuildRun and checking that the results are valid tags.
+func ValidateTagTemplates(b *buildv1alpha1.Build) error {
+	data := TagTemplateData{
+		Build:     TagTemplateObject{Name: b.Name, Namespace: b.Namespace},
+		BuildRun:  TagTemplateObject{Name: b.Name + "-abcde", Namespace: b.Namespace},
+		Revision:  TagTemplateRevision{SHA: "0123456789abcdef0123456789abcdef01234567", ShortSHA: "0123456", Branch: "feature/main"},
+		Timestamp: "20060102150405",
+	}
+
+	tags, err := RenderTags(b.Spec.Output.Tags, data)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := registry.ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
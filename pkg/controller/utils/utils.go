@@ -30,3 +30,20 @@ func IsBuilderImageDefined(b *buildv1alpha1.Build) bool {
 	}
 	return true
 }
+
+// IsSigningDefined inspect if build has `.spec.output.signing` defined, making sure the secret of
+// the signing key is informed.
+func IsSigningDefined(b *buildv1alpha1.Build) bool {
+	if b.Spec.Output.Signing == nil {
+		return false
+	}
+	if b.Spec.Output.Signing.SecretRef.Name == "" {
+		return false
+	}
+	return true
+}
+
+// IsSBOMDefined inspect if build has `.spec.output.sbom` defined.
+func IsSBOMDefined(b *buildv1alpha1.Build) bool {
+	return b.Spec.Output.SBOM != nil
+}
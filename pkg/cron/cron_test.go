@@ -0,0 +1,96 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cron_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/cron"
+)
+
+var _ = Describe("Schedule", func() {
+	// Wednesday, 7 October 2020
+	start := time.Date(2020, 10, 7, 8, 30, 15, 0, time.UTC)
+
+	next := func(expression string, t time.Time) time.Time {
+		schedule, err := cron.Parse(expression)
+		Expect(err).ToNot(HaveOccurred())
+		return schedule.Next(t)
+	}
+
+	Context("computing the next time", func() {
+		It("should return the next minute for every minute", func() {
+			Expect(next("* * * * *", start)).To(Equal(time.Date(2020, 10, 7, 8, 31, 0, 0, time.UTC)))
+		})
+
+		It("should return a time strictly after the provided time", func() {
+			at := time.Date(2020, 10, 7, 2, 0, 0, 0, time.UTC)
+			Expect(next("0 2 * * *", at)).To(Equal(time.Date(2020, 10, 8, 2, 0, 0, 0, time.UTC)))
+		})
+
+		It("should support lists, ranges and steps", func() {
+			Expect(next("*/15 9-17 * * *", start)).To(Equal(time.Date(2020, 10, 7, 9, 0, 0, 0, time.UTC)))
+			Expect(next("10,40 8 * * *", start)).To(Equal(time.Date(2020, 10, 7, 8, 40, 0, 0, time.UTC)))
+			Expect(next("0 0-12/6 * * *", start)).To(Equal(time.Date(2020, 10, 7, 12, 0, 0, 0, time.UTC)))
+			Expect(next("5/20 * * * *", start)).To(Equal(time.Date(2020, 10, 7, 8, 45, 0, 0, time.UTC)))
+		})
+
+		It("should support the names of months and days of the week", func() {
+			Expect(next("0 3 * * sat", start)).To(Equal(time.Date(2020, 10, 10, 3, 0, 0, 0, time.UTC)))
+			Expect(next("0 0 1 jan *", start)).To(Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should treat 7 as Sunday", func() {
+			Expect(next("0 0 * * 7", start)).To(Equal(time.Date(2020, 10, 11, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should match either the day of month or the day of week if both are restricted", func() {
+			// the 15th is a Thursday, the next Friday is the 9th
+			Expect(next("0 0 15 * fri", start)).To(Equal(time.Date(2020, 10, 9, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should apply a step of the day of month together with the day of week", func() {
+			// */2 matches the odd days of the month, the Monday 12 October is skipped
+			Expect(next("0 0 */2 * mon", start)).To(Equal(time.Date(2020, 10, 19, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should skip months without the day", func() {
+			Expect(next("0 0 31 * *", start)).To(Equal(time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC)))
+			Expect(next("0 0 31 * *", time.Date(2020, 10, 31, 1, 0, 0, 0, time.UTC))).To(Equal(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should support the macros", func() {
+			Expect(next("@daily", start)).To(Equal(time.Date(2020, 10, 8, 0, 0, 0, 0, time.UTC)))
+			Expect(next("@hourly", start)).To(Equal(time.Date(2020, 10, 7, 9, 0, 0, 0, time.UTC)))
+			Expect(next("@weekly", start)).To(Equal(time.Date(2020, 10, 11, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should return the zero time for a day that never exists", func() {
+			Expect(next("0 0 30 2 *", start).IsZero()).To(BeTrue())
+		})
+	})
+
+	Context("parsing invalid expressions", func() {
+		It("should fail for a wrong number of fields", func() {
+			_, err := cron.Parse("* * * *")
+			Expect(err).To(MatchError(`the cron expression "* * * *" must have five fields, but has 4`))
+		})
+
+		It("should fail for values out of range", func() {
+			_, err := cron.Parse("60 * * * *")
+			Expect(err).To(MatchError(`invalid minute "60": the value 60 is not between 0 and 59`))
+		})
+
+		It("should fail for invalid ranges, steps and names", func() {
+			for _, expression := range []string{"* 5-2 * * *", "*/0 * * * *", "* * * foo *", "* * 0 * *"} {
+				_, err := cron.Parse(expression)
+				Expect(err).To(HaveOccurred(), expression)
+			}
+		})
+	})
+})
@@ -0,0 +1,188 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package placeholder resolves placeholders of the form $(name) in texts. Placeholders
+// belong to a namespace, which is the first segment of their name, for example the
+// placeholder $(build.output.image) belongs to the namespace build. Only placeholders
+// of the namespace of a Resolver are resolved, all others are kept as they are, so
+// that Tekton variables or shell command substitutions are not touched.
+//
+// A placeholder is escaped by doubling its dollar sign, $$(build.output.image) is
+// rendered as the literal text $(build.output.image).
+package placeholder
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Build is the namespace of the placeholders that build strategies can use
+	Build = "build"
+
+	// OutputImage is the placeholder for the URL of the output image
+	OutputImage = "build.output.image"
+
+	// BuilderImage is the placeholder for the URL of the builder image
+	BuilderImage = "build.builder.image"
+
+	// Dockerfile is the placeholder for the path to the Dockerfile
+	Dockerfile = "build.dockerfile"
+
+	// SourceContextDir is the placeholder for the context directory in the source
+	SourceContextDir = "build.source.contextDir"
+
+	// ImageDigestResultPath is the placeholder for the path of the file to which a build
+	// strategy writes the digest of the image that it pushed
+	ImageDigestResultPath = "build.results.imageDigest.path"
+
+	// ImageSizeResultPath is the placeholder for the path of the file to which a build
+	// strategy writes the compressed size in bytes of the image that it pushed
+	ImageSizeResultPath = "build.results.imageSize.path"
+
+	// parametersPrefix is the prefix of the placeholders for the strategy parameters
+	parametersPrefix = "build.parameters."
+)
+
+// BuildPlaceholders are the placeholders that build strategies can use in addition to the
+// placeholders of their parameters
+var BuildPlaceholders = []string{
+	OutputImage,
+	BuilderImage,
+	Dockerfile,
+	SourceContextDir,
+	ImageDigestResultPath,
+	ImageSizeResultPath,
+}
+
+// Parameter returns the name of the placeholder for the strategy parameter with the provided name
+func Parameter(name string) string {
+	return parametersPrefix + name
+}
+
+// ParameterName returns the name of the strategy parameter that is referenced by the
+// placeholder, and whether the placeholder references a strategy parameter at all
+func ParameterName(placeholder string) (string, bool) {
+	if !strings.HasPrefix(placeholder, parametersPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(placeholder, parametersPrefix), true
+}
+
+// Reference is the occurrence of a placeholder in a text
+type Reference struct {
+	// Name is the name of the placeholder, without the surrounding $( and )
+	Name string
+
+	// Complete is true when the placeholder makes up the complete text
+	Complete bool
+}
+
+// Resolver replaces the placeholders of a namespace with their values
+type Resolver struct {
+	namespace string
+	values    map[string]string
+}
+
+// NewResolver returns a Resolver for the placeholders of the provided namespace, which
+// replaces the placeholders with the provided values
+func NewResolver(namespace string, values map[string]string) *Resolver {
+	return &Resolver{
+		namespace: namespace,
+		values:    values,
+	}
+}
+
+// References returns all placeholders of the namespace of the Resolver in the text, in
+// the order of their appearance. Escaped placeholders are ignored.
+func (r *Resolver) References(text string) []Reference {
+	var references []Reference
+	r.scan(text, func(name string) (string, error) {
+		references = append(references, Reference{
+			Name:     name,
+			Complete: text == "$("+name+")",
+		})
+		return "", nil
+	})
+	return references
+}
+
+// Validate returns an error for the first placeholder of the namespace of the
+// Resolver in the text, for which the Resolver has no value
+func (r *Resolver) Validate(text string) error {
+	_, err := r.Resolve(text)
+	return err
+}
+
+// Resolve returns the text in which the placeholders of the namespace of the Resolver
+// are replaced with their values, and escaped placeholders are unescaped. It fails for
+// placeholders, for which the Resolver has no value.
+func (r *Resolver) Resolve(text string) (string, error) {
+	return r.scan(text, func(name string) (string, error) {
+		value, ok := r.values[name]
+		if !ok {
+			return "", fmt.Errorf("unknown placeholder \"$(%s)\"", name)
+		}
+		return value, nil
+	})
+}
+
+// inNamespace returns whether the placeholder name belongs to the namespace of the Resolver
+func (r *Resolver) inNamespace(name string) bool {
+	return name == r.namespace || strings.HasPrefix(name, r.namespace+".")
+}
+
+// scan walks through the text and calls resolve for every placeholder of the namespace of
+// the Resolver, the returned text contains the values that resolve returned instead of the
+// placeholders
+func (r *Resolver) scan(text string, resolve func(name string) (string, error)) (string, error) {
+	var result strings.Builder
+
+	for {
+		start := strings.Index(text, "$(")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(text[start:], ")")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := text[start+2 : end]
+
+		// a placeholder inside of a command substitution, continue with it
+		if strings.Contains(name, "$(") {
+			result.WriteString(text[:start+2])
+			text = text[start+2:]
+			continue
+		}
+
+		if !r.inNamespace(name) {
+			result.WriteString(text[:end+1])
+			text = text[end+1:]
+			continue
+		}
+
+		// an escaped placeholder is written without its escaping dollar sign
+		if start > 0 && text[start-1] == '$' {
+			result.WriteString(text[:start-1])
+			result.WriteString(text[start : end+1])
+			text = text[end+1:]
+			continue
+		}
+
+		value, err := resolve(name)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(text[:start])
+		result.WriteString(value)
+		text = text[end+1:]
+	}
+
+	result.WriteString(text)
+	return result.String(), nil
+}
@@ -0,0 +1,17 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package placeholder_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPlaceholder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Placeholder Suite")
+}
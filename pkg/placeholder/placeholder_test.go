@@ -0,0 +1,84 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package placeholder_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/shipwright-io/build/pkg/placeholder"
+)
+
+var _ = Describe("Resolver", func() {
+	var resolver *Resolver
+
+	BeforeEach(func() {
+		resolver = NewResolver(Build, map[string]string{
+			OutputImage:        "$(inputs.params.OUTPUT_IMAGE)",
+			Parameter("flags"): "$(inputs.params.PARAM_flags)",
+		})
+	})
+
+	Context("listing the references in a text", func() {
+		It("should return the placeholders of the namespace in the order of their appearance", func() {
+			Expect(resolver.References("--tag=$(build.output.image) $(build.parameters.flags)")).To(Equal([]Reference{
+				{Name: OutputImage},
+				{Name: Parameter("flags")},
+			}))
+		})
+
+		It("should mark a placeholder that makes up the complete text", func() {
+			Expect(resolver.References("$(build.parameters.flags)")).To(Equal([]Reference{
+				{Name: Parameter("flags"), Complete: true},
+			}))
+		})
+
+		It("should ignore escaped placeholders and placeholders of other namespaces", func() {
+			Expect(resolver.References("$$(build.output.image) $(inputs.params.FOO) $(workspace)")).To(BeEmpty())
+		})
+
+		It("should find placeholders inside of a command substitution", func() {
+			Expect(resolver.References("$(echo $(build.output.image))")).To(Equal([]Reference{
+				{Name: OutputImage},
+			}))
+		})
+	})
+
+	Context("resolving a text", func() {
+		It("should replace the placeholders of the namespace with their values", func() {
+			Expect(resolver.Resolve("--tag=$(build.output.image) $(build.parameters.flags)")).To(Equal("--tag=$(inputs.params.OUTPUT_IMAGE) $(inputs.params.PARAM_flags)"))
+		})
+
+		It("should keep placeholders of other namespaces and unterminated placeholders", func() {
+			Expect(resolver.Resolve("$(workspace) $(date) $((1+1)) $(build.output.image")).To(Equal("$(workspace) $(date) $((1+1)) $(build.output.image"))
+		})
+
+		It("should unescape escaped placeholders", func() {
+			Expect(resolver.Resolve("echo $$(build.output.image) is $(build.output.image)")).To(Equal("echo $(build.output.image) is $(inputs.params.OUTPUT_IMAGE)"))
+		})
+
+		It("should keep the escaping of placeholders of other namespaces", func() {
+			Expect(resolver.Resolve("echo $$(date)")).To(Equal("echo $$(date)"))
+		})
+
+		It("should fail for an unknown placeholder", func() {
+			_, err := resolver.Resolve("--tag=$(build.outptu.image)")
+			Expect(err).To(MatchError("unknown placeholder \"$(build.outptu.image)\""))
+			Expect(resolver.Validate("--tag=$(build.outptu.image)")).To(HaveOccurred())
+			Expect(resolver.Validate("--tag=$(build.output.image)")).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("parameter placeholders", func() {
+		It("should return the name of the referenced parameter", func() {
+			name, ok := ParameterName(Parameter("flags"))
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("flags"))
+
+			_, ok = ParameterName(OutputImage)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
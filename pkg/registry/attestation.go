@@ -0,0 +1,114 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	imageManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	imageConfigMediaType   = "application/vnd.oci.image.config.v1+json"
+	inTotoMediaType        = "application/vnd.in-toto+json"
+	blobMediaType          = "application/octet-stream"
+)
+
+// imageManifest is an OCI image manifest
+type imageManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        descriptor   `json:"config"`
+	Layers        []descriptor `json:"layers"`
+}
+
+// AttestationTag returns the tag under which the attestation of the image with the digest is
+// stored, it is the tag sha256-<hash>.provenance. The statement is not wrapped in the DSSE
+// envelope that cosign expects under its sha256-<hash>.att tag, so it must not use that tag.
+func AttestationTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".provenance"
+}
+
+// PushAttestation stores the in-toto statement as attestation of the image with the digest in
+// the repository of the image, and returns the reference of the attestation. The statement is
+// the only layer of an OCI artifact under the tag of AttestationTag. The credentials need push
+// access and are optional.
+func (r *Resolver) PushAttestation(ctx context.Context, image string, digest string, statement []byte, credentials *Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("the attestation requires the sha256 digest of %s", ref)
+	}
+
+	config := []byte("{}")
+	m := imageManifest{
+		SchemaVersion: 2,
+		MediaType:     imageManifestMediaType,
+		Config:        descriptor{MediaType: imageConfigMediaType, Digest: blobDigest(config), Size: len(config)},
+		Layers:        []descriptor{{MediaType: inTotoMediaType, Digest: blobDigest(statement), Size: len(statement)}},
+	}
+	for _, blob := range [][]byte{config, statement} {
+		if err := r.pushBlob(ctx, ref, blob, credentials); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	tag := AttestationTag(digest)
+	response, err := r.request(ctx, http.MethodPut, ref.manifestURL(tag), ref, credentials, &manifest{data: data, mediaType: imageManifestMediaType})
+	if err != nil {
+		return "", err
+	}
+	drain(response)
+
+	return fmt.Sprintf("%s/%s:%s", ref.Registry, ref.Repository, tag), nil
+}
+
+// pushBlob uploads the blob to the repository of the reference in a single request, after the
+// upload was started
+func (r *Resolver) pushBlob(ctx context.Context, ref Reference, blob []byte, credentials *Credentials) error {
+	uploadURL := ref.blobUploadURL()
+	response, err := r.request(ctx, http.MethodPost, uploadURL, ref, credentials, &manifest{mediaType: blobMediaType})
+	if err != nil {
+		return err
+	}
+	drain(response)
+
+	// the location of the upload can be relative to the URL that started it
+	base, err := url.Parse(uploadURL)
+	if err != nil {
+		return err
+	}
+	location, err := base.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+	query := location.Query()
+	query.Set("digest", blobDigest(blob))
+	location.RawQuery = query.Encode()
+
+	response, err = r.request(ctx, http.MethodPut, location.String(), ref, credentials, &manifest{data: blob, mediaType: blobMediaType})
+	if err != nil {
+		return err
+	}
+	drain(response)
+	return nil
+}
+
+// blobDigest returns the sha256 digest of the blob
+func blobDigest(blob []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(blob))
+}
@@ -0,0 +1,70 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// dockerConfig is the content of a secret of type kubernetes.io/dockerconfigjson
+type dockerConfig struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	} `json:"auths"`
+}
+
+// CredentialsFromDockerConfig returns the credentials for the registry of the image from the
+// content of a .dockerconfigjson, or nil if it has none for the registry
+func CredentialsFromDockerConfig(data []byte, image string) (*Credentials, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	config := dockerConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse the docker config: %v", err)
+	}
+
+	for server, auth := range config.Auths {
+		if registryHost(server) != ref.Registry {
+			continue
+		}
+
+		if auth.Username != "" || auth.Password != "" {
+			return &Credentials{Username: auth.Username, Password: auth.Password}, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the auth of %s in the docker config: %v", server, err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("the auth of %s in the docker config is no username and password", server)
+		}
+		return &Credentials{Username: parts[0], Password: parts[1]}, nil
+	}
+	return nil, nil
+}
+
+// registryHost returns the registry of a server in a docker config, which can be a URL like
+// https://index.docker.io/v1/
+func registryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	switch host {
+	case "index.docker.io", dockerHubAPI:
+		return dockerHub
+	default:
+		return host
+	}
+}
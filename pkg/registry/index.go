@@ -0,0 +1,115 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const indexMediaType = "application/vnd.oci.image.index.v1+json"
+
+// Platform is the operating system and architecture that an image runs on
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ParsePlatform parses a platform like linux/amd64 or linux/arm/v7
+func ParsePlatform(platform string) (Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Platform{}, fmt.Errorf("the platform %q is not of the form os/architecture[/variant]", platform)
+	}
+	for _, part := range parts {
+		if part == "" || strings.TrimSpace(part) != part {
+			return Platform{}, fmt.Errorf("the platform %q is not of the form os/architecture[/variant]", platform)
+		}
+	}
+
+	p := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// String returns the platform in the form os/architecture[/variant]
+func (p Platform) String() string {
+	if p.Variant != "" {
+		return p.OS + "/" + p.Architecture + "/" + p.Variant
+	}
+	return p.OS + "/" + p.Architecture
+}
+
+// PlatformImage is the image of a platform in the repository of an image index
+type PlatformImage struct {
+	Platform Platform
+	Digest   string
+}
+
+// descriptor is an entry of an image index
+type descriptor struct {
+	MediaType string    `json:"mediaType"`
+	Digest    string    `json:"digest"`
+	Size      int       `json:"size"`
+	Platform  *Platform `json:"platform,omitempty"`
+}
+
+// index is an OCI image index
+type index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+// PushIndex pushes an image index of the platform images to the tag of the image, and returns
+// the digest of the index. The platform images must already be pushed to the repository of the
+// image. The credentials need push access and are optional.
+func (r *Resolver) PushIndex(ctx context.Context, image string, images []PlatformImage, credentials *Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return "", fmt.Errorf("the image index cannot be pushed to the digest of %s", ref)
+	}
+
+	// the index describes the manifests with their media types and sizes, which are taken
+	// from the manifests in the registry
+	idx := index{SchemaVersion: 2, MediaType: indexMediaType}
+	for _, image := range images {
+		m, err := r.getManifest(ctx, ref, image.Digest, credentials)
+		if err != nil {
+			return "", err
+		}
+
+		platform := image.Platform
+		idx.Manifests = append(idx.Manifests, descriptor{
+			MediaType: m.mediaType,
+			Digest:    image.Digest,
+			Size:      len(m.data),
+			Platform:  &platform,
+		})
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := r.request(ctx, http.MethodPut, ref.manifestURL(ref.Tag), ref, credentials, &manifest{data: data, mediaType: indexMediaType})
+	if err != nil {
+		return "", err
+	}
+	drain(response)
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
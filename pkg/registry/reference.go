@@ -0,0 +1,121 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// dockerHub is the registry of image references without registry host
+	dockerHub = "docker.io"
+
+	// dockerHubAPI is the host that serves the registry API of Docker Hub
+	dockerHubAPI = "registry-1.docker.io"
+
+	defaultTag = "latest"
+)
+
+// Reference is a parsed image reference, like quay.io/shipwright/base:1.0
+type Reference struct {
+	// Registry is the host of the registry, with port if any
+	Registry string
+
+	// Repository is the path of the repository in the registry
+	Repository string
+
+	// Tag is the tag of the image, it is latest if the reference has neither tag nor digest
+	Tag string
+
+	// Digest is the digest of the image if the reference has one
+	Digest string
+}
+
+// ParseReference parses an image reference. The registry defaults to Docker Hub, where
+// images without path are in the library repository.
+func ParseReference(image string) (Reference, error) {
+	ref := Reference{}
+	rest := strings.TrimSpace(image)
+	if rest == "" {
+		return ref, fmt.Errorf("the image reference is empty")
+	}
+
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+		if !strings.Contains(ref.Digest, ":") {
+			return ref, fmt.Errorf("the image reference %q has an invalid digest", image)
+		}
+	}
+
+	// a colon after the last slash separates the tag, a colon before it the port of the registry
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	// the first path component is a registry if it looks like a host
+	if i := strings.Index(rest, "/"); i >= 0 && (strings.ContainsAny(rest[:i], ".:") || rest[:i] == "localhost") {
+		ref.Registry = rest[:i]
+		rest = rest[i+1:]
+	} else {
+		ref.Registry = dockerHub
+	}
+	if ref.Registry == dockerHub && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+
+	if rest == "" {
+		return ref, fmt.Errorf("the image reference %q has no repository", image)
+	}
+	ref.Repository = rest
+	return ref, nil
+}
+
+// String returns the reference with registry and repository, and the digest, or the tag if
+// there is no digest
+func (r Reference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s/%s@%s", r.Registry, r.Repository, r.Digest)
+	}
+	return fmt.Sprintf("%s/%s:%s", r.Registry, r.Repository, r.Tag)
+}
+
+// apiHost returns the host that serves the registry API
+func (r Reference) apiHost() string {
+	if r.Registry == dockerHub || r.Registry == "index.docker.io" {
+		return dockerHubAPI
+	}
+	return r.Registry
+}
+
+// scheme returns http for registries on the local host, like a development registry on
+// localhost:5000, and https otherwise
+func (r Reference) scheme() string {
+	host := r.Registry
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+	switch strings.Trim(host, "[]") {
+	case "localhost", "127.0.0.1", "::1":
+		return "http"
+	default:
+		return "https"
+	}
+}
+
+// manifestURL returns the URL of the manifest with the tag or digest in the repository
+func (r Reference) manifestURL(reference string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", r.scheme(), r.apiHost(), r.Repository, reference)
+}
+
+// blobUploadURL returns the URL that starts the upload of a blob to the repository
+func (r Reference) blobUploadURL() string {
+	return fmt.Sprintf("%s://%s/v2/%s/blobs/uploads/", r.scheme(), r.apiHost(), r.Repository)
+}
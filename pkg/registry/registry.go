@@ -0,0 +1,338 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registry resolves image references to the digests of the images through the API of
+// the container registry, so that a change of the image behind a tag can be noticed, adds
+// tags to images that are already pushed, and pushes the image indexes of multi-arch images.
+// Registries that require a bearer token, like Docker Hub, and registries that accept basic
+// auth are supported.
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const digestHeader = "Docker-Content-Digest"
+
+// tagPattern matches the valid tags of an image
+var tagPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+
+// manifestMediaTypes are the manifests that the resolver accepts, an index of a multi-arch
+// image is preferred, so that the digest is the one that the tag points to
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Credentials are the username and password for a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Resolver resolves image references to digests
+type Resolver struct {
+	httpClient *http.Client
+}
+
+// NewResolver returns a Resolver that uses the HTTP client, or a default client if it is nil
+func NewResolver(httpClient *http.Client) *Resolver {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Resolver{httpClient: httpClient}
+}
+
+// Resolve returns the digest of the image that the reference points to. A reference with a
+// digest is returned as is. The credentials are optional.
+func (r *Resolver) Resolve(ctx context.Context, image string, credentials *Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	manifestURL := ref.manifestURL(ref.Tag)
+
+	// HEAD requests do not count towards the pull rate limits of some registries, a GET
+	// is only needed if the registry does not return the digest in a header
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		response, err := r.request(ctx, method, manifestURL, ref, credentials, nil)
+		if err != nil {
+			return "", err
+		}
+
+		digest := response.Header.Get(digestHeader)
+		if digest == "" && method == http.MethodGet {
+			hash := sha256.New()
+			if _, err := io.Copy(hash, response.Body); err != nil {
+				response.Body.Close()
+				return "", err
+			}
+			digest = fmt.Sprintf("sha256:%x", hash.Sum(nil))
+		}
+		response.Body.Close()
+
+		if digest != "" {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("the registry did not return the digest of %s", ref)
+}
+
+// ValidateTag returns an error if the tag is not a valid tag of an image
+func ValidateTag(tag string) error {
+	if !tagPattern.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q", tag)
+	}
+	return nil
+}
+
+// Tag adds the tags to the image with the digest in the repository of the image, by uploading
+// its manifest again under every tag. The layers of the image are not copied, they are already
+// in the repository. The credentials need push access and are optional.
+func (r *Resolver) Tag(ctx context.Context, image string, digest string, tags []string, credentials *Credentials) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	m, err := r.getManifest(ctx, ref, digest, credentials)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		response, err := r.request(ctx, http.MethodPut, ref.manifestURL(tag), ref, credentials, m)
+		if err != nil {
+			return err
+		}
+		drain(response)
+	}
+	return nil
+}
+
+// manifest is the content of an image manifest that is uploaded to the registry
+type manifest struct {
+	data      []byte
+	mediaType string
+}
+
+// getManifest downloads the manifest with the digest in the repository of the reference
+func (r *Resolver) getManifest(ctx context.Context, ref Reference, digest string, credentials *Credentials) (*manifest, error) {
+	response, err := r.request(ctx, http.MethodGet, ref.manifestURL(digest), ref, credentials, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &manifest{data: data, mediaType: response.Header.Get("Content-Type")}
+	if m.mediaType == "" {
+		// a manifest declares its media type, only the very first image manifests did not
+		probe := struct {
+			MediaType string `json:"mediaType"`
+		}{}
+		if err := json.Unmarshal(data, &probe); err != nil || probe.MediaType == "" {
+			return nil, fmt.Errorf("the registry did not return the media type of the manifest of %s@%s", ref, digest)
+		}
+		m.mediaType = probe.MediaType
+	}
+	return m, nil
+}
+
+// request requests the manifest, or uploads it if one is given, and authenticates if the
+// registry challenges the request
+func (r *Resolver) request(ctx context.Context, method string, manifestURL string, ref Reference, credentials *Credentials, m *manifest) (*http.Response, error) {
+	response, err := r.do(ctx, method, manifestURL, "", m)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		drain(response)
+
+		actions := "pull"
+		if m != nil {
+			actions = "pull,push"
+		}
+		authorization, err := r.authorize(ctx, challenge, ref, credentials, actions)
+		if err != nil {
+			return nil, err
+		}
+		if response, err = r.do(ctx, method, manifestURL, authorization, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		drain(response)
+		return nil, fmt.Errorf("%s %s: unexpected response status %s", method, manifestURL, response.Status)
+	}
+	return response, nil
+}
+
+func (r *Resolver) do(ctx context.Context, method string, url string, authorization string, m *manifest) (*http.Response, error) {
+	var body io.Reader
+	if m != nil {
+		body = bytes.NewReader(m.data)
+	}
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+	if m != nil {
+		request.Header.Set("Content-Type", m.mediaType)
+	} else {
+		request.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	return r.httpClient.Do(request)
+}
+
+// authorize returns the Authorization header for the challenge of the registry. For a Bearer
+// challenge, it requests a token for the actions from the token service of the registry.
+func (r *Resolver) authorize(ctx context.Context, challenge string, ref Reference, credentials *Credentials, actions string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil {
+			return "", fmt.Errorf("the registry %s requires credentials", ref.Registry)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials.Username+":"+credentials.Password)), nil
+
+	case "bearer":
+		token, err := r.token(ctx, params, ref, credentials, actions)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+
+	default:
+		return "", fmt.Errorf("the registry %s requires the unsupported authentication %q", ref.Registry, challenge)
+	}
+}
+
+// token requests a token with access for the actions, like pull or pull,push, to the repository
+// from the token service
+func (r *Resolver) token(ctx context.Context, params map[string]string, ref Reference, credentials *Credentials, actions string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("the registry %s did not name a token service", ref.Registry)
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:%s", ref.Repository, actions))
+	tokenURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	request = request.WithContext(ctx)
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: unexpected response status %s", realm, response.Status)
+	}
+
+	result := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if result.Token != "" {
+		return result.Token, nil
+	}
+	if result.AccessToken != "" {
+		return result.AccessToken, nil
+	}
+	return "", fmt.Errorf("the token service of the registry %s did not return a token", ref.Registry)
+}
+
+// parseChallenge parses a WWW-Authenticate header like
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	challenge = strings.TrimSpace(challenge)
+	i := strings.Index(challenge, " ")
+	if i < 0 {
+		return challenge, params
+	}
+
+	scheme, rest := challenge[:i], challenge[i+1:]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else if end := strings.Index(rest, ","); end >= 0 {
+			value, rest = rest[:end], rest[end:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimLeft(rest, ", ")
+	}
+	return scheme, params
+}
+
+func drain(response *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+}
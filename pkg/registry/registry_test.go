@@ -0,0 +1,397 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/shipwright-io/build/pkg/registry"
+)
+
+const manifest = `{"schemaVersion": 2, "mediaType": "application/vnd.docker.distribution.manifest.v2+json"}`
+
+var manifestDigest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(manifest)))
+
+var _ = Describe("ParseReference", func() {
+	DescribeTable("parses image references",
+		func(image string, expected Reference) {
+			ref, err := ParseReference(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ref).To(Equal(expected))
+		},
+		Entry("official image", "alpine", Reference{Registry: "docker.io", Repository: "library/alpine", Tag: "latest"}),
+		Entry("Docker Hub image with tag", "docker.io/paketobuildpacks/builder:full", Reference{Registry: "docker.io", Repository: "paketobuildpacks/builder", Tag: "full"}),
+		Entry("registry with path", "quay.io/shipwright/base/ubi:8", Reference{Registry: "quay.io", Repository: "shipwright/base/ubi", Tag: "8"}),
+		Entry("registry with port", "localhost:5000/base", Reference{Registry: "localhost:5000", Repository: "base", Tag: "latest"}),
+		Entry("digest", "quay.io/shipwright/base@sha256:0123", Reference{Registry: "quay.io", Repository: "shipwright/base", Digest: "sha256:0123"}),
+	)
+
+	It("fails for an empty reference", func() {
+		_, err := ParseReference(" ")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails for an invalid digest", func() {
+		_, err := ParseReference("quay.io/shipwright/base@0123")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Resolver", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		resolver *Resolver
+		ctx      context.Context
+		image    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		image = strings.TrimPrefix(server.URL, "http://") + "/shipwright/base:1.0"
+		resolver = NewResolver(server.Client())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	serveManifest := func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/v2/shipwright/base/manifests/1.0"))
+		Expect(r.Header.Get("Accept")).To(ContainSubstring("application/vnd.docker.distribution.manifest.list.v2+json"))
+		w.Header().Set("Docker-Content-Digest", manifestDigest)
+		w.Write([]byte(manifest))
+	}
+
+	It("returns the digest of the tag", func() {
+		methods := []string{}
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			serveManifest(w, r)
+		}
+
+		digest, err := resolver.Resolve(ctx, image, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+		Expect(methods).To(Equal([]string{http.MethodHead}))
+	})
+
+	It("computes the digest of the manifest if the registry does not return it", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(manifest))
+		}
+
+		digest, err := resolver.Resolve(ctx, image, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("returns the digest of a reference with digest without asking the registry", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Fail("unexpected request")
+		}
+
+		digest, err := resolver.Resolve(ctx, "quay.io/shipwright/base@sha256:0123", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal("sha256:0123"))
+	})
+
+	It("requests a token from the token service of the registry", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/token":
+				Expect(r.URL.Query().Get("service")).To(Equal("registry.test"))
+				Expect(r.URL.Query().Get("scope")).To(Equal("repository:shipwright/base:pull"))
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("user"))
+				Expect(password).To(Equal("pass"))
+				w.Write([]byte(`{"token": "t0k3n"}`))
+
+			case r.Header.Get("Authorization") == "Bearer t0k3n":
+				serveManifest(w, r)
+
+			default:
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test",scope="repository:shipwright/base:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+
+		digest, err := resolver.Resolve(ctx, image, &Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+	})
+
+	It("authenticates with basic auth if the registry requires it", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); ok && username == "user" && password == "pass" {
+				serveManifest(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+
+		digest, err := resolver.Resolve(ctx, image, &Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(manifestDigest))
+
+		_, err = resolver.Resolve(ctx, image, nil)
+		Expect(err).To(MatchError(ContainSubstring("requires credentials")))
+	})
+
+	It("fails if the tag does not exist", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		_, err := resolver.Resolve(ctx, image, nil)
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	Context("tagging an image", func() {
+		var uploaded map[string]string
+
+		// serveTagging serves the manifest by its digest, and records the uploaded manifests by their tag
+		serveTagging := func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				Expect(r.URL.Path).To(Equal("/v2/shipwright/base/manifests/" + manifestDigest))
+				w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+				w.Write([]byte(manifest))
+			case http.MethodPut:
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/vnd.docker.distribution.manifest.v2+json"))
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+				uploaded[strings.TrimPrefix(r.URL.Path, "/v2/shipwright/base/manifests/")] = string(body)
+				w.WriteHeader(http.StatusCreated)
+			}
+		}
+
+		BeforeEach(func() {
+			uploaded = map[string]string{}
+		})
+
+		It("uploads the manifest of the digest under every tag", func() {
+			handler = serveTagging
+
+			err := resolver.Tag(ctx, image, manifestDigest, []string{"latest", "abc1234"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploaded).To(Equal(map[string]string{"latest": manifest, "abc1234": manifest}))
+		})
+
+		It("requests a token with push access", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					scope := r.URL.Query().Get("scope")
+					Expect(scope).To(BeElementOf("repository:shipwright/base:pull", "repository:shipwright/base:pull,push"))
+					w.Write([]byte(`{"token": "` + scope + `"}`))
+
+				case r.Method == http.MethodGet && r.Header.Get("Authorization") == "Bearer repository:shipwright/base:pull",
+					r.Method == http.MethodPut && r.Header.Get("Authorization") == "Bearer repository:shipwright/base:pull,push":
+					serveTagging(w, r)
+
+				default:
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}
+
+			err := resolver.Tag(ctx, image, manifestDigest, []string{"latest"}, &Credentials{Username: "user", Password: "pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploaded).To(HaveKey("latest"))
+		})
+
+		It("fails for an invalid tag without asking the registry", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				Fail("unexpected request")
+			}
+
+			err := resolver.Tag(ctx, image, manifestDigest, []string{"feature/login"}, nil)
+			Expect(err).To(MatchError(ContainSubstring("invalid tag")))
+		})
+
+		It("fails if the registry rejects the upload", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				serveTagging(w, r)
+			}
+
+			err := resolver.Tag(ctx, image, manifestDigest, []string{"latest"}, nil)
+			Expect(err).To(MatchError(ContainSubstring("403")))
+		})
+	})
+
+	Context("pushing an image index", func() {
+		var uploaded map[string]string
+
+		BeforeEach(func() {
+			uploaded = map[string]string{}
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					Expect(r.URL.Path).To(Equal("/v2/shipwright/base/manifests/" + manifestDigest))
+					w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+					w.Write([]byte(manifest))
+				case http.MethodPut:
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/vnd.oci.image.index.v1+json"))
+					body, err := ioutil.ReadAll(r.Body)
+					Expect(err).ToNot(HaveOccurred())
+					uploaded[strings.TrimPrefix(r.URL.Path, "/v2/shipwright/base/manifests/")] = string(body)
+					w.WriteHeader(http.StatusCreated)
+				}
+			}
+		})
+
+		It("uploads an index of the platform images to the tag and returns its digest", func() {
+			digest, err := resolver.PushIndex(ctx, image, []PlatformImage{
+				{Platform: Platform{OS: "linux", Architecture: "amd64"}, Digest: manifestDigest},
+				{Platform: Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, Digest: manifestDigest},
+			}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploaded).To(HaveKey("1.0"))
+			Expect(digest).To(Equal(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(uploaded["1.0"])))))
+
+			size := len(manifest)
+			Expect(uploaded["1.0"]).To(MatchJSON(fmt.Sprintf(`{
+				"schemaVersion": 2,
+				"mediaType": "application/vnd.oci.image.index.v1+json",
+				"manifests": [
+					{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "%[1]s", "size": %[2]d, "platform": {"os": "linux", "architecture": "amd64"}},
+					{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "%[1]s", "size": %[2]d, "platform": {"os": "linux", "architecture": "arm", "variant": "v7"}}
+				]
+			}`, manifestDigest, size)))
+		})
+
+		It("fails for an image with a digest", func() {
+			_, err := resolver.PushIndex(ctx, image+"@"+manifestDigest, nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(uploaded).To(BeEmpty())
+		})
+	})
+
+	Context("pushing an attestation", func() {
+		const statement = `{"_type": "https://in-toto.io/Statement/v0.1"}`
+
+		var blobs, uploaded map[string]string
+
+		BeforeEach(func() {
+			blobs, uploaded = map[string]string{}, map[string]string{}
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				switch {
+				case r.Method == http.MethodPost:
+					Expect(r.URL.Path).To(Equal("/v2/shipwright/base/blobs/uploads/"))
+					w.Header().Set("Location", "/v2/shipwright/base/blobs/uploads/0123?state=abc")
+					w.WriteHeader(http.StatusAccepted)
+				case strings.HasPrefix(r.URL.Path, "/v2/shipwright/base/blobs/uploads/"):
+					Expect(r.URL.Query().Get("state")).To(Equal("abc"))
+					blobs[r.URL.Query().Get("digest")] = string(body)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/vnd.oci.image.manifest.v1+json"))
+					uploaded[strings.TrimPrefix(r.URL.Path, "/v2/shipwright/base/manifests/")] = string(body)
+					w.WriteHeader(http.StatusCreated)
+				}
+			}
+		})
+
+		It("uploads the statement as layer of a manifest under the attestation tag of the digest", func() {
+			reference, err := resolver.PushAttestation(ctx, image, manifestDigest, []byte(statement), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			tag := AttestationTag(manifestDigest)
+			Expect(tag).To(Equal(strings.Replace(manifestDigest, ":", "-", 1) + ".provenance"))
+			Expect(reference).To(Equal(strings.TrimSuffix(image, ":1.0") + ":" + tag))
+
+			statementDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(statement)))
+			configDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("{}")))
+			Expect(blobs).To(Equal(map[string]string{statementDigest: statement, configDigest: "{}"}))
+			Expect(uploaded).To(HaveKey(tag))
+			Expect(uploaded[tag]).To(MatchJSON(fmt.Sprintf(`{
+				"schemaVersion": 2,
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"config": {"mediaType": "application/vnd.oci.image.config.v1+json", "digest": "%s", "size": 2},
+				"layers": [{"mediaType": "application/vnd.in-toto+json", "digest": "%s", "size": %d}]
+			}`, configDigest, statementDigest, len(statement))))
+		})
+
+		It("fails without the digest of the image", func() {
+			_, err := resolver.PushAttestation(ctx, image, "", []byte(statement), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(blobs).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("ParsePlatform", func() {
+	DescribeTable("parses platforms",
+		func(platform string, expected Platform) {
+			p, err := ParsePlatform(platform)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p).To(Equal(expected))
+			Expect(p.String()).To(Equal(platform))
+		},
+		Entry("os and architecture", "linux/amd64", Platform{OS: "linux", Architecture: "amd64"}),
+		Entry("variant", "linux/arm/v7", Platform{OS: "linux", Architecture: "arm", Variant: "v7"}),
+	)
+
+	DescribeTable("fails for invalid platforms",
+		func(platform string) {
+			_, err := ParsePlatform(platform)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("architecture only", "amd64"),
+		Entry("empty os", "/amd64"),
+		Entry("too many parts", "linux/arm/v7/extra"),
+	)
+})
+
+var _ = Describe("CredentialsFromDockerConfig", func() {
+	It("returns the username and password of the registry", func() {
+		config := `{"auths": {"quay.io": {"username": "user", "password": "pass"}}}`
+
+		credentials, err := CredentialsFromDockerConfig([]byte(config), "quay.io/shipwright/base:1.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials).To(Equal(&Credentials{Username: "user", Password: "pass"}))
+	})
+
+	It("decodes the auth of Docker Hub", func() {
+		auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+		config := `{"auths": {"https://index.docker.io/v1/": {"auth": "` + auth + `"}}}`
+
+		credentials, err := CredentialsFromDockerConfig([]byte(config), "paketobuildpacks/builder:full")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials).To(Equal(&Credentials{Username: "user", Password: "pass"}))
+	})
+
+	It("returns no credentials for other registries", func() {
+		config := `{"auths": {"quay.io": {"username": "user", "password": "pass"}}}`
+
+		credentials, err := CredentialsFromDockerConfig([]byte(config), "alpine")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials).To(BeNil())
+	})
+})
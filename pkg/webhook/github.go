@@ -0,0 +1,135 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"net/http"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+const (
+	gitHubEventHeader        = "X-GitHub-Event"
+	gitHubSignatureHeader    = "X-Hub-Signature"
+	gitHubSignature256Header = "X-Hub-Signature-256"
+)
+
+// gitHubRepository holds the URLs of a repository in a GitHub webhook
+type gitHubRepository struct {
+	CloneURL string `json:"clone_url"`
+	HTMLURL  string `json:"html_url"`
+	SSHURL   string `json:"ssh_url"`
+}
+
+func (r *gitHubRepository) urls() []string {
+	return []string{r.CloneURL, r.HTMLURL, r.SSHURL}
+}
+
+// gitHubPush is the payload of a push webhook
+type gitHubPush struct {
+	Ref        string           `json:"ref"`
+	After      string           `json:"after"`
+	Deleted    bool             `json:"deleted"`
+	Repository gitHubRepository `json:"repository"`
+}
+
+// gitHubPullRequest is the payload of a pull_request webhook
+type gitHubPullRequest struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Head struct {
+			Sha string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository gitHubRepository `json:"repository"`
+}
+
+// gitHub parses the webhooks of GitHub, they are signed with an HMAC of the payload
+type gitHub struct{}
+
+func (g *gitHub) accepts(request *http.Request) bool {
+	return request.Header.Get(gitHubEventHeader) != ""
+}
+
+func (g *gitHub) parse(request *http.Request, payload []byte) (*event, error) {
+	switch request.Header.Get(gitHubEventHeader) {
+	case "push":
+		push := gitHubPush{}
+		if err := json.Unmarshal(payload, &push); err != nil {
+			return nil, err
+		}
+
+		// tags and deleted branches are not built
+		if push.Deleted || !strings.HasPrefix(push.Ref, "refs/heads/") {
+			return nil, nil
+		}
+
+		return &event{
+			eventType:      buildv1alpha1.WebhookEventPush,
+			repositoryURLs: push.Repository.urls(),
+			branch:         strings.TrimPrefix(push.Ref, "refs/heads/"),
+			sha:            push.After,
+		}, nil
+
+	case "pull_request":
+		pullRequest := gitHubPullRequest{}
+		if err := json.Unmarshal(payload, &pullRequest); err != nil {
+			return nil, err
+		}
+
+		// only new commits are built, not for example a changed title
+		switch pullRequest.Action {
+		case "opened", "reopened", "synchronize":
+		default:
+			return nil, nil
+		}
+
+		return &event{
+			eventType:      buildv1alpha1.WebhookEventPullRequest,
+			repositoryURLs: pullRequest.Repository.urls(),
+			branch:         pullRequest.PullRequest.Base.Ref,
+			sha:            pullRequest.PullRequest.Head.Sha,
+		}, nil
+
+	default:
+		// for example the ping event when the webhook is created
+		return nil, nil
+	}
+}
+
+func (g *gitHub) verify(request *http.Request, payload []byte, secret []byte) bool {
+	if signature := request.Header.Get(gitHubSignature256Header); signature != "" {
+		return verifyHMAC(sha256.New, "sha256=", signature, payload, secret)
+	}
+	if signature := request.Header.Get(gitHubSignatureHeader); signature != "" {
+		return verifyHMAC(sha1.New, "sha1=", signature, payload, secret)
+	}
+	return false
+}
+
+// verifyHMAC compares the signature to the HMAC of the payload in constant time
+func verifyHMAC(hashFunc func() hash.Hash, prefix string, signature string, payload []byte, secret []byte) bool {
+	if !strings.HasPrefix(signature, prefix) {
+		return false
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(hashFunc, secret)
+	mac.Write(payload)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
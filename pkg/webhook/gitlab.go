@@ -0,0 +1,109 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+const (
+	gitLabEventHeader = "X-Gitlab-Event"
+	gitLabTokenHeader = "X-Gitlab-Token"
+)
+
+// gitLabProject holds the URLs of a project in a GitLab webhook
+type gitLabProject struct {
+	GitHTTPURL string `json:"git_http_url"`
+	GitSSHURL  string `json:"git_ssh_url"`
+	WebURL     string `json:"web_url"`
+}
+
+func (p *gitLabProject) urls() []string {
+	return []string{p.GitHTTPURL, p.GitSSHURL, p.WebURL}
+}
+
+// gitLabPush is the payload of a Push Hook
+type gitLabPush struct {
+	Ref         string        `json:"ref"`
+	CheckoutSha string        `json:"checkout_sha"`
+	Project     gitLabProject `json:"project"`
+}
+
+// gitLabMergeRequest is the payload of a Merge Request Hook
+type gitLabMergeRequest struct {
+	ObjectAttributes struct {
+		Action       string `json:"action"`
+		OldRev       string `json:"oldrev"`
+		TargetBranch string `json:"target_branch"`
+		LastCommit   struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+	Project gitLabProject `json:"project"`
+}
+
+// gitLab parses the webhooks of GitLab, they carry the secret as token
+type gitLab struct{}
+
+func (g *gitLab) accepts(request *http.Request) bool {
+	return request.Header.Get(gitLabEventHeader) != ""
+}
+
+func (g *gitLab) parse(request *http.Request, payload []byte) (*event, error) {
+	switch request.Header.Get(gitLabEventHeader) {
+	case "Push Hook":
+		push := gitLabPush{}
+		if err := json.Unmarshal(payload, &push); err != nil {
+			return nil, err
+		}
+
+		// a deleted branch has no checkout SHA
+		if push.CheckoutSha == "" || !strings.HasPrefix(push.Ref, "refs/heads/") {
+			return nil, nil
+		}
+
+		return &event{
+			eventType:      buildv1alpha1.WebhookEventPush,
+			repositoryURLs: push.Project.urls(),
+			branch:         strings.TrimPrefix(push.Ref, "refs/heads/"),
+			sha:            push.CheckoutSha,
+		}, nil
+
+	case "Merge Request Hook":
+		mergeRequest := gitLabMergeRequest{}
+		if err := json.Unmarshal(payload, &mergeRequest); err != nil {
+			return nil, err
+		}
+
+		// only new commits are built, an update without previous revision changed for example the title
+		attributes := mergeRequest.ObjectAttributes
+		switch {
+		case attributes.Action == "open", attributes.Action == "reopen":
+		case attributes.Action == "update" && attributes.OldRev != "":
+		default:
+			return nil, nil
+		}
+
+		return &event{
+			eventType:      buildv1alpha1.WebhookEventPullRequest,
+			repositoryURLs: mergeRequest.Project.urls(),
+			branch:         attributes.TargetBranch,
+			sha:            attributes.LastCommit.ID,
+		}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (g *gitLab) verify(request *http.Request, _ []byte, secret []byte) bool {
+	token := request.Header.Get(gitLabTokenHeader)
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), secret) == 1
+}
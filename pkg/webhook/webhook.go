@@ -0,0 +1,332 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook receives the push and pull request webhooks of GitHub and GitLab, and creates
+// BuildRuns for the Builds whose webhook trigger matches the event. The webhook secret of a Build
+// verifies that the event is authentic, before a BuildRun for the Build is created.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// Path is the URL path on which the webhooks are received
+	Path = "/webhooks/git"
+
+	// TriggerName is the value of the trigger annotation of BuildRuns that a webhook created
+	TriggerName = "webhook"
+
+	// maxPayloadSize is the maximum size of a webhook payload that GitHub sends
+	maxPayloadSize = 25 * 1024 * 1024
+
+	// defaultRevision is the branch that a Build without revision builds
+	defaultRevision = "master"
+)
+
+// event is a push or pull request event of a git provider
+type event struct {
+	// eventType is the type of the event
+	eventType buildv1alpha1.WebhookEventType
+
+	// repositoryURLs are the URLs through which the repository can be cloned
+	repositoryURLs []string
+
+	// branch is the pushed branch, or the target branch of a pull request
+	branch string
+
+	// sha is the commit that is built
+	sha string
+}
+
+// provider parses and verifies the webhooks of a git provider
+type provider interface {
+	// accepts returns true if the request is a webhook of the provider
+	accepts(request *http.Request) bool
+
+	// parse returns the event of a webhook, or nil if the webhook does not create BuildRuns
+	parse(request *http.Request, payload []byte) (*event, error)
+
+	// verify returns true if the webhook was sent with the provided secret
+	verify(request *http.Request, payload []byte, secret []byte) bool
+}
+
+// Receiver is the http.Handler that receives the webhooks and creates the BuildRuns
+type Receiver struct {
+	ctx       context.Context
+	client    client.Client
+	providers []provider
+}
+
+// NewReceiver returns a new Receiver that looks up the Builds, their secrets and creates the
+// BuildRuns with the provided client
+func NewReceiver(ctx context.Context, c client.Client) *Receiver {
+	return &Receiver{
+		ctx:       ctx,
+		client:    c,
+		providers: []provider{&gitHub{}, &gitLab{}},
+	}
+}
+
+// ServeHTTP handles a webhook request
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var p provider
+	for _, candidate := range rc.providers {
+		if candidate.accepts(request) {
+			p = candidate
+			break
+		}
+	}
+	if p == nil {
+		http.Error(w, "the request is no GitHub or GitLab webhook", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, request.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read the payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ev, err := p.parse(request, payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse the payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	if ev == nil {
+		fmt.Fprintln(w, "the event does not trigger builds")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(rc.ctx, 30*time.Second)
+	defer cancel()
+
+	builds := &buildv1alpha1.BuildList{}
+	if err := rc.client.List(ctx, builds); err != nil {
+		ctxlog.Error(ctx, err, "failed to list the Builds for a webhook")
+		http.Error(w, "failed to list the Builds", http.StatusInternalServerError)
+		return
+	}
+
+	var created []string
+	unverified := 0
+	for i := range builds.Items {
+		build := &builds.Items[i]
+		if !matches(build, ev) {
+			continue
+		}
+
+		secret, err := rc.getWebhookSecret(ctx, build)
+		if err != nil {
+			ctxlog.Error(ctx, err, "failed to retrieve the webhook secret", "namespace", build.Namespace, "name", build.Name)
+			unverified++
+			continue
+		}
+		if !p.verify(request, payload, secret) {
+			ctxlog.Info(ctx, "ignoring a webhook with an invalid secret", "namespace", build.Namespace, "name", build.Name)
+			unverified++
+			continue
+		}
+
+		buildRun := newBuildRun(build, ev)
+		if err := rc.client.Create(ctx, buildRun); err != nil {
+			ctxlog.Error(ctx, err, "failed to create a BuildRun for a webhook", "namespace", build.Namespace, "name", build.Name)
+			http.Error(w, "failed to create a BuildRun", http.StatusInternalServerError)
+			return
+		}
+		ctxlog.Info(ctx, "created a BuildRun for a webhook", "namespace", buildRun.Namespace, "name", buildRun.Name, "Build", build.Name, "commit", ev.sha)
+		created = append(created, fmt.Sprintf("%s/%s", buildRun.Namespace, buildRun.Name))
+	}
+
+	if len(created) == 0 && unverified > 0 {
+		http.Error(w, "the webhook secret is invalid", http.StatusUnauthorized)
+		return
+	}
+	if len(created) == 0 {
+		fmt.Fprintln(w, "no Build matches the event")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "created BuildRuns: %s\n", strings.Join(created, ", "))
+}
+
+// getWebhookSecret returns the webhook secret of the webhook trigger of a Build
+func (rc *Receiver) getWebhookSecret(ctx context.Context, build *buildv1alpha1.Build) ([]byte, error) {
+	secretRef := build.Spec.Triggers.Webhook.SecretRef
+
+	secret := &corev1.Secret{}
+	if err := rc.client.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: build.Namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	value, ok := secret.Data[buildv1alpha1.WebhookSecretKey]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret %s has no key %s", secretRef.Name, buildv1alpha1.WebhookSecretKey)
+	}
+	return value, nil
+}
+
+// matches returns true if the webhook trigger of the Build applies to the event
+func matches(build *buildv1alpha1.Build, ev *event) bool {
+	if build.Spec.Triggers == nil || build.Spec.Triggers.Webhook == nil {
+		return false
+	}
+	trigger := build.Spec.Triggers.Webhook
+
+	events := trigger.Events
+	if len(events) == 0 {
+		events = []buildv1alpha1.WebhookEventType{buildv1alpha1.WebhookEventPush}
+	}
+	if !containsEventType(events, ev.eventType) {
+		return false
+	}
+
+	sourceURL := normalizeURL(build.Spec.Source.URL)
+	matchesURL := false
+	for _, repositoryURL := range ev.repositoryURLs {
+		if repositoryURL != "" && normalizeURL(repositoryURL) == sourceURL {
+			matchesURL = true
+			break
+		}
+	}
+	if !matchesURL {
+		return false
+	}
+
+	branches := trigger.Branches
+	if len(branches) == 0 {
+		revision := defaultRevision
+		if build.Spec.Source.Revision != nil {
+			revision = *build.Spec.Source.Revision
+		}
+		branches = []string{revision}
+	}
+	for _, pattern := range branches {
+		if matched, err := path.Match(pattern, ev.branch); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEventType(events []buildv1alpha1.WebhookEventType, eventType buildv1alpha1.WebhookEventType) bool {
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeURL reduces the http(s), ssh and scp-like URLs of a repository to its host and path,
+// so that the different URLs of the same repository are equal
+func normalizeURL(repositoryURL string) string {
+	u := strings.ToLower(strings.TrimSpace(repositoryURL))
+
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	} else if i := strings.Index(u, ":"); i >= 0 {
+		// scp-like syntax, for example git@github.com:owner/repo.git
+		u = u[:i] + "/" + u[i+1:]
+	}
+
+	host, repoPath := u, ""
+	if i := strings.Index(u, "/"); i >= 0 {
+		host, repoPath = u[:i], u[i:]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if i := strings.Index(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+
+	return host + strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+}
+
+// newBuildRun returns the BuildRun that builds the commit of the event
+func newBuildRun(build *buildv1alpha1.Build, ev *event) *buildv1alpha1.BuildRun {
+	sha := ev.sha
+	return &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: build.Name + "-",
+			Namespace:    build.Namespace,
+			Annotations: map[string]string{
+				buildv1alpha1.AnnotationBuildRunTrigger: TriggerName,
+			},
+		},
+		Spec: buildv1alpha1.BuildRunSpec{
+			BuildRef: &buildv1alpha1.BuildRef{
+				Name: build.Name,
+			},
+			Revision: &sha,
+		},
+	}
+}
+
+// server serves the Receiver, it implements the manager.Runnable interface
+type server struct {
+	ctx     context.Context
+	address string
+	handler http.Handler
+}
+
+// Add creates the webhook Receiver and adds a server for it to the Manager, which starts it
+// together with the controllers
+func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContext(ctx, "webhook")
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, NewReceiver(ctx, mgr.GetClient()))
+
+	return mgr.Add(&server{ctx: ctx, address: c.WebhookListenAddress, handler: mux})
+}
+
+// Start serves the webhooks until the stop channel is closed
+func (s *server) Start(stop <-chan struct{}) error {
+	srv := &http.Server{Addr: s.address, Handler: s.handler}
+
+	errs := make(chan error, 1)
+	go func() {
+		ctxlog.Info(s.ctx, "serving webhooks", "address", s.address, "path", Path)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns false, so that every replica of the operator receives webhooks
+func (s *server) NeedLeaderElection() bool {
+	return false
+}
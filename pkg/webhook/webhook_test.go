@@ -0,0 +1,294 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sha        = "a8b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5"
+	secretName = "webhook-secret"
+	secret     = "s3cr3t"
+)
+
+var gitHubPush = `{
+  "ref": "refs/heads/master",
+  "after": "` + sha + `",
+  "deleted": false,
+  "repository": {
+    "clone_url": "https://github.com/shipwright-io/sample-go.git",
+    "html_url": "https://github.com/shipwright-io/sample-go",
+    "ssh_url": "git@github.com:shipwright-io/sample-go.git"
+  }
+}`
+
+var gitHubPullRequest = `{
+  "action": "synchronize",
+  "pull_request": {
+    "head": {"sha": "` + sha + `"},
+    "base": {"ref": "release-1.0"}
+  },
+  "repository": {
+    "clone_url": "https://github.com/shipwright-io/sample-go.git"
+  }
+}`
+
+var gitLabPush = `{
+  "ref": "refs/heads/master",
+  "checkout_sha": "` + sha + `",
+  "project": {
+    "git_http_url": "https://gitlab.com/shipwright-io/sample-go.git",
+    "git_ssh_url": "git@gitlab.com:shipwright-io/sample-go.git",
+    "web_url": "https://gitlab.com/shipwright-io/sample-go"
+  }
+}`
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func gitHubRequest(eventType string, payload string, signature string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, webhook.Path, strings.NewReader(payload))
+	request.Header.Set("X-GitHub-Event", eventType)
+	request.Header.Set("X-Hub-Signature-256", signature)
+	return request
+}
+
+func gitLabRequest(eventType string, payload string, token string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, webhook.Path, strings.NewReader(payload))
+	request.Header.Set("X-Gitlab-Event", eventType)
+	request.Header.Set("X-Gitlab-Token", token)
+	return request
+}
+
+var _ = Describe("Receiver", func() {
+	var (
+		client    *fakes.FakeClient
+		receiver  *webhook.Receiver
+		builds    []build.Build
+		buildRuns []*build.BuildRun
+	)
+
+	newBuild := func(name string, url string, trigger *build.WebhookTrigger) build.Build {
+		b := build.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: build.BuildSpec{
+				Source: build.GitSource{URL: url},
+			},
+		}
+		if trigger != nil {
+			b.Spec.Triggers = &build.Triggers{Webhook: trigger}
+		}
+		return b
+	}
+
+	serve := func(request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	BeforeEach(func() {
+		builds, buildRuns = nil, nil
+
+		client = &fakes.FakeClient{}
+		client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+			object.(*build.BuildList).Items = builds
+			return nil
+		})
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			if s, ok := object.(*corev1.Secret); ok && nn.Name == secretName {
+				s.Data = map[string][]byte{build.WebhookSecretKey: []byte(secret)}
+				return nil
+			}
+			return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.CreateCalls(func(_ context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+			buildRuns = append(buildRuns, object.(*build.BuildRun))
+			return nil
+		})
+
+		receiver = webhook.NewReceiver(ctxlog.NewContext(context.TODO(), "webhook-test"), client)
+	})
+
+	Context("receiving a GitHub push", func() {
+		BeforeEach(func() {
+			builds = []build.Build{
+				newBuild("sample-go", "https://github.com/shipwright-io/sample-go", &build.WebhookTrigger{
+					SecretRef: corev1.LocalObjectReference{Name: secretName},
+				}),
+				newBuild("without-trigger", "https://github.com/shipwright-io/sample-go", nil),
+				newBuild("other-repository", "https://github.com/shipwright-io/sample-java", &build.WebhookTrigger{
+					SecretRef: corev1.LocalObjectReference{Name: secretName},
+				}),
+			}
+		})
+
+		It("creates a BuildRun of the pushed commit for the Build with the repository and branch", func() {
+			response := serve(gitHubRequest("push", gitHubPush, sign(gitHubPush)))
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+
+			Expect(buildRuns).To(HaveLen(1))
+			buildRun := buildRuns[0]
+			Expect(buildRun.Namespace).To(Equal("default"))
+			Expect(buildRun.GenerateName).To(Equal("sample-go-"))
+			Expect(buildRun.Spec.BuildRef.Name).To(Equal("sample-go"))
+			Expect(*buildRun.Spec.Revision).To(Equal(sha))
+			Expect(buildRun.Annotations).To(HaveKeyWithValue(build.AnnotationBuildRunTrigger, "webhook"))
+		})
+
+		It("matches the ssh URL of the repository", func() {
+			builds[0].Spec.Source.URL = "git@github.com:shipwright-io/sample-go.git"
+
+			response := serve(gitHubRequest("push", gitHubPush, sign(gitHubPush)))
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+			Expect(buildRuns).To(HaveLen(1))
+		})
+
+		It("rejects an invalid signature", func() {
+			response := serve(gitHubRequest("push", gitHubPush, "sha256=0123"))
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+			Expect(buildRuns).To(BeEmpty())
+		})
+
+		It("rejects a request without signature", func() {
+			response := serve(gitHubRequest("push", gitHubPush, ""))
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+			Expect(buildRuns).To(BeEmpty())
+		})
+
+		It("ignores a push to another branch than the revision of the source", func() {
+			revision := "develop"
+			builds[0].Spec.Source.Revision = &revision
+
+			response := serve(gitHubRequest("push", gitHubPush, sign(gitHubPush)))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(buildRuns).To(BeEmpty())
+		})
+
+		It("applies the branch patterns of the trigger", func() {
+			builds[0].Spec.Triggers.Webhook.Branches = []string{"main", "mas*"}
+
+			response := serve(gitHubRequest("push", gitHubPush, sign(gitHubPush)))
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+			Expect(buildRuns).To(HaveLen(1))
+		})
+
+		It("ignores a push if the trigger only accepts pull requests", func() {
+			builds[0].Spec.Triggers.Webhook.Events = []build.WebhookEventType{build.WebhookEventPullRequest}
+
+			response := serve(gitHubRequest("push", gitHubPush, sign(gitHubPush)))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(buildRuns).To(BeEmpty())
+		})
+
+		It("ignores the push of a tag", func() {
+			payload := strings.Replace(gitHubPush, "refs/heads/master", "refs/tags/v1.0.0", 1)
+
+			response := serve(gitHubRequest("push", payload, sign(payload)))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(buildRuns).To(BeEmpty())
+		})
+	})
+
+	Context("receiving a GitHub pull request", func() {
+		BeforeEach(func() {
+			builds = []build.Build{
+				newBuild("sample-go", "https://github.com/shipwright-io/sample-go", &build.WebhookTrigger{
+					SecretRef: corev1.LocalObjectReference{Name: secretName},
+					Events:    []build.WebhookEventType{build.WebhookEventPush, build.WebhookEventPullRequest},
+					Branches:  []string{"release-*"},
+				}),
+			}
+		})
+
+		It("creates a BuildRun of the head commit for the target branch", func() {
+			response := serve(gitHubRequest("pull_request", gitHubPullRequest, sign(gitHubPullRequest)))
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+
+			Expect(buildRuns).To(HaveLen(1))
+			Expect(*buildRuns[0].Spec.Revision).To(Equal(sha))
+		})
+
+		It("ignores a pull request that was closed", func() {
+			payload := strings.Replace(gitHubPullRequest, "synchronize", "closed", 1)
+
+			response := serve(gitHubRequest("pull_request", payload, sign(payload)))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(buildRuns).To(BeEmpty())
+		})
+	})
+
+	Context("receiving a GitLab push", func() {
+		BeforeEach(func() {
+			builds = []build.Build{
+				newBuild("sample-go", "https://gitlab.com/shipwright-io/sample-go.git", &build.WebhookTrigger{
+					SecretRef: corev1.LocalObjectReference{Name: secretName},
+				}),
+			}
+		})
+
+		It("creates a BuildRun if the token matches the secret", func() {
+			response := serve(gitLabRequest("Push Hook", gitLabPush, secret))
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+
+			Expect(buildRuns).To(HaveLen(1))
+			Expect(*buildRuns[0].Spec.Revision).To(Equal(sha))
+		})
+
+		It("rejects an invalid token", func() {
+			response := serve(gitLabRequest("Push Hook", gitLabPush, "wrong"))
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+			Expect(buildRuns).To(BeEmpty())
+		})
+	})
+
+	Context("receiving other requests", func() {
+		It("accepts the GitHub ping event without creating BuildRuns", func() {
+			response := serve(gitHubRequest("ping", `{"zen": "Keep it logically awesome."}`, ""))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(client.ListCallCount()).To(Equal(0))
+		})
+
+		It("rejects requests that are no webhooks of a supported provider", func() {
+			response := serve(httptest.NewRequest(http.MethodPost, webhook.Path, strings.NewReader("{}")))
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("rejects other methods than POST", func() {
+			response := serve(httptest.NewRequest(http.MethodGet, webhook.Path, nil))
+			Expect(response.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+
+		It("rejects an invalid payload", func() {
+			response := serve(gitHubRequest("push", "{", sign("{")))
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
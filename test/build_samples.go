@@ -80,3 +80,26 @@ spec:
     image: image-registry.openshift-image-registry.svc:5000/example/buildpacks-app
   timeout: 30s
 `
+
+// BuildahBuildWithParameters defines a Build for
+// Buildah with source, strategy, output and
+// parameters
+const BuildahBuildWithParameters = `
+apiVersion: build.dev/v1alpha1
+kind: Build
+metadata:
+  name: buildah
+  namespace: build-test
+spec:
+  source:
+    url: "https://github.com/sbose78/taxi"
+  strategy:
+    name: buildah
+  output:
+    image: image-registry.openshift-image-registry.svc:5000/example/buildpacks-app
+  parameters:
+    - name: storage-driver
+      value: vfs
+    - name: buildah-tag
+      value: v1.16
+`
@@ -48,6 +48,31 @@ spec:
     image: image-registry.openshift-image-registry.svc:5000/example/buildpacks-app-v2
 `
 
+// BuildahBuildRunWithOverrides defines a BuildRun
+// with a service-account and overrides for the
+// revision, builder image and parameters
+const BuildahBuildRunWithOverrides = `
+apiVersion: build.dev/v1alpha1
+kind: BuildRun
+metadata:
+  name: buildah-run
+  namespace: build-test
+spec:
+  buildRef:
+    name: buildah
+  serviceAccount:
+    name: buildpacks-v3-serviceaccount
+  revision: feature-branch
+  builder:
+    image: quay.io/buildah/stable:v1.17
+  parameters:
+    - name: buildah-tag
+      value: v1.17
+    - name: build-args
+      values:
+        - --build-arg=A=1
+`
+
 // BuildpacksBuildRunWithSA defines a BuildRun
 // with a service-account
 const BuildpacksBuildRunWithSA = `
@@ -45,6 +45,7 @@ spec:
       args:
         - push
         - --tls-verify=false
+        - --digestfile=$(build.results.imageDigest.path)
         - docker://$(build.output.image)
       resources:
         limits:
@@ -127,3 +128,60 @@ spec:
         - name: varlibcontainers
           mountPath: /var/lib/containers
 `
+
+// BuildahBuildStrategyWithParameters defines a
+// BuildStrategy for Buildah with a single step
+// that references the declared parameters
+const BuildahBuildStrategyWithParameters = `
+apiVersion: build.dev/v1alpha1
+kind: BuildStrategy
+metadata:
+  name: buildah
+spec:
+  parameters:
+    - name: storage-driver
+      description: The storage driver of buildah
+    - name: buildah-tag
+      description: The tag of the buildah image
+      default: latest
+    - name: build-args
+      description: Additional arguments for buildah bud
+      type: array
+      defaults: []
+  buildSteps:
+    - name: build
+      image: quay.io/buildah/stable:$(build.parameters.buildah-tag)
+      workingDir: /workspace/source
+      command:
+        - buildah
+        - bud
+        - --storage-driver=$(build.parameters.storage-driver)
+        - $(build.parameters.build-args)
+        - -t
+        - $(build.output.image)
+        - $(build.source.contextDir)
+      env:
+        - name: STORAGE_DRIVER
+          value: $(build.parameters.storage-driver)
+`
+
+// BuildahBuildStrategyWithUnknownPlaceholder defines a
+// BuildStrategy for Buildah with a single step
+// that references an unknown placeholder
+const BuildahBuildStrategyWithUnknownPlaceholder = `
+apiVersion: build.dev/v1alpha1
+kind: BuildStrategy
+metadata:
+  name: buildah
+spec:
+  buildSteps:
+    - name: build
+      image: quay.io/buildah/stable:latest
+      workingDir: /workspace/source
+      command:
+        - buildah
+        - bud
+        - --tag=$(build.outptu.image)
+        - --file=$(build.dockerfile)
+        - $(build.source.contextDir)
+`
@@ -11,6 +11,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -225,13 +226,16 @@ func (c *Catalog) SecretList(name string) corev1.SecretList {
 
 // StubFunc is used to simulate the status of the Build
 // after a .Status().Update() call in the controller. This
-// receives a parameter to return an specific status state
-func (c *Catalog) StubFunc(status corev1.ConditionStatus, reason string) func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+// receives the expected status and message of the Ready condition
+func (c *Catalog) StubFunc(status corev1.ConditionStatus, message string) func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
 	return func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
 		switch object := object.(type) {
 		case *build.Build:
-			Expect(object.Status.Registered).To(Equal(status))
-			Expect(object.Status.Reason).To(ContainSubstring(reason))
+			Expect(object.Status.ObservedGeneration).To(Equal(object.Generation))
+			condition := object.Status.GetCondition(build.BuildConditionReady)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(status))
+			Expect(condition.Message).To(ContainSubstring(message))
 		}
 		return nil
 	}
@@ -319,9 +323,11 @@ func (c *Catalog) StubBuildRunStatus(reason string, name *string, status corev1.
 	return func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
 		switch object := object.(type) {
 		case *build.BuildRun:
-			if !tolerateEmptyStatus || object.Status.Succeeded != "" {
-				Expect(object.Status.Succeeded).To(Equal(status))
-				Expect(object.Status.Reason).To(Equal(reason))
+			condition := object.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+			if !tolerateEmptyStatus || condition != nil {
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(status))
+				Expect(condition.Reason).To(Equal(reason))
 				Expect(object.Status.LatestTaskRunRef).To(Equal(name))
 			}
 			if object.Status.BuildSpec != nil {
@@ -577,12 +583,18 @@ func (c *Catalog) DefaultBuild(buildName string, strategyName string, strategyKi
 			},
 		},
 		Status: build.BuildStatus{
-			Registered: corev1.ConditionTrue,
+			Conditions: corev1alpha1.Conditions{
+				{
+					Type:   build.BuildConditionReady,
+					Status: corev1.ConditionTrue,
+					Reason: build.BuildReasonSucceeded,
+				},
+			},
 		},
 	}
 }
 
-// DefaultBuildWithFalseRegistered returns a minimal Build object with a FALSE Registered
+// DefaultBuildWithFalseRegistered returns a minimal Build object with a FALSE Ready condition
 func (c *Catalog) DefaultBuildWithFalseRegistered(buildName string, strategyName string, strategyKind build.BuildStrategyKind) *build.Build {
 	return &build.Build{
 		ObjectMeta: metav1.ObjectMeta{
@@ -595,8 +607,14 @@ func (c *Catalog) DefaultBuildWithFalseRegistered(buildName string, strategyName
 			},
 		},
 		Status: build.BuildStatus{
-			Registered: corev1.ConditionFalse,
-			Reason:     "something bad happened",
+			Conditions: corev1alpha1.Conditions{
+				{
+					Type:    build.BuildConditionReady,
+					Status:  corev1.ConditionFalse,
+					Reason:  build.BuildReasonSecretNotFound,
+					Message: "something bad happened",
+				},
+			},
 		},
 	}
 }
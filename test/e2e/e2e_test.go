@@ -273,7 +273,7 @@ var _ = Describe("For a Kubernetes cluster with Tekton and build installed", fun
 			br, err = buildRunTestData(namespace, testID, "test/data/buildrun_timeout.yaml")
 			Expect(err).ToNot(HaveOccurred())
 
-			validateBuildRunToFail(namespace, br, "kaniko-timeout.*failed to finish within \"15s\"")
+			validateBuildRunToFail(namespace, br, "Timeout", "kaniko-timeout.*failed to finish within \"15s\"")
 		})
 	})
 
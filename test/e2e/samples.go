@@ -126,14 +126,17 @@ func printTestFailureDebugInfo(namespace string, buildRunName string) {
 
 	buildRun, build, err := retrieveBuildAndBuildRun(namespace, buildRunName)
 	if build != nil {
-		Logf("The status of Build %s: registered=%s, reason=%s", build.Name, build.Status.Registered, build.Status.Reason)
+		for _, condition := range build.Status.Conditions {
+			Logf("The status of Build %s: %s=%s, reason=%s, message=%s", build.Name, condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
 		if buildJSON, err := json.Marshal(build); err == nil {
 			Logf("The full Build: %s", string(buildJSON))
 		}
 	}
 
 	if buildRun != nil {
-		Logf("The status of BuildRun %s: succeeded=%s, reason=%s", buildRun.Name, buildRun.Status.Succeeded, buildRun.Status.Reason)
+		condition := getSucceededCondition(buildRun)
+		Logf("The status of BuildRun %s: succeeded=%s, reason=%s, message=%s", buildRun.Name, condition.Status, condition.Reason, condition.Message)
 		if buildRunJSON, err := json.Marshal(buildRun); err == nil {
 			Logf("The full BuildRun: %s", string(buildRunJSON))
 		}
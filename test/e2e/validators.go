@@ -22,6 +22,7 @@ import (
 	framework "github.com/operator-framework/operator-sdk/pkg/test"
 	operatorapis "github.com/shipwright-io/build/pkg/apis"
 	operator "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/shipwright-io/build/pkg/apis/core/v1alpha1"
 	v1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -215,7 +216,7 @@ func validateBuildRunToSucceed(
 			Logf("Retrieving BuildRun error: '%s'", err)
 			return ""
 		}
-		return testBuildRun.Status.Reason
+		return getSucceededCondition(testBuildRun).Reason
 	}, time.Duration(30*getTimeoutMultiplier())*time.Second, 2*time.Second).Should(BeElementOf(pendingAndRunningStatues), "BuildRun is not pending or running")
 
 	// Verify that the BuildSpec is available in the status
@@ -226,7 +227,7 @@ func validateBuildRunToSucceed(
 		err = clientGet(buildRunNsName, testBuildRun)
 		Expect(err).ToNot(HaveOccurred(), "Error retrieving a buildRun")
 
-		return testBuildRun.Status.Reason
+		return getSucceededCondition(testBuildRun).Reason
 	}, time.Duration(180*getTimeoutMultiplier())*time.Second, 3*time.Second).Should(Equal(runningStatus), "BuildRun REASON is not running")
 
 	// Verify that the BuildSpec is still available in the status
@@ -237,7 +238,7 @@ func validateBuildRunToSucceed(
 		err = clientGet(buildRunNsName, testBuildRun)
 		Expect(err).ToNot(HaveOccurred(), "Error retrieving a buildRun")
 
-		return testBuildRun.Status.Succeeded
+		return getSucceededCondition(testBuildRun).Status
 	}, time.Duration(550*getTimeoutMultiplier())*time.Second, 5*time.Second).Should(Equal(trueCondition), "BuildRun did not succeed")
 
 	// Verify that the BuildSpec is still available in the status
@@ -247,11 +248,12 @@ func validateBuildRunToSucceed(
 }
 
 // validateBuildRunToFail creates the build run and watches its flow until it fails
-// and verifies the reason using a regular expression.
+// and verifies the reason, and the message using a regular expression.
 func validateBuildRunToFail(
 	namespace string,
 	testBuildRun *operator.BuildRun,
-	expectedReasonRegexp string,
+	expectedReason string,
+	expectedMessageRegexp string,
 ) {
 	f := framework.Global
 	falseCondition := corev1.ConditionFalse
@@ -266,14 +268,24 @@ func validateBuildRunToFail(
 		err = clientGet(buildRunNsName, testBuildRun)
 		Expect(err).ToNot(HaveOccurred(), "Error retrieving build run")
 
-		return testBuildRun.Status.Succeeded
+		return getSucceededCondition(testBuildRun).Status
 	}, time.Duration(550*getTimeoutMultiplier())*time.Second, 5*time.Second).Should(Equal(falseCondition), "BuildRun did not fail")
 
 	// Verify that the BuildSpec is available in the status
 	Expect(testBuildRun.Status.BuildSpec).ToNot(BeNil())
 
 	// Verify the build run failure
-	Expect(testBuildRun.Status.Reason).To(MatchRegexp(expectedReasonRegexp))
+	Expect(getSucceededCondition(testBuildRun).Reason).To(Equal(expectedReason))
+	Expect(getSucceededCondition(testBuildRun).Message).To(MatchRegexp(expectedMessageRegexp))
+}
+
+// getSucceededCondition returns the Succeeded condition of the build run, or an empty
+// condition with an Unknown status if the build run does not have it yet
+func getSucceededCondition(buildRun *operator.BuildRun) corev1alpha1.Condition {
+	if condition := buildRun.Status.GetCondition(corev1alpha1.ConditionSucceeded); condition != nil {
+		return *condition
+	}
+	return corev1alpha1.Condition{Type: corev1alpha1.ConditionSucceeded, Status: corev1.ConditionUnknown}
 }
 
 // validateBuildDeletion verifies if the BuildRun is deleted after Build is deleted.
@@ -311,7 +323,7 @@ func validateBuildDeletion(
 // validateServiceAccountDeletion validates that a service account is correctly deleted after the end of
 // a build run and depending on the state of the build run
 func validateServiceAccountDeletion(buildRun *operator.BuildRun, namespace string) {
-	if buildRun.Status.Succeeded == "" || buildRun.Status.Succeeded == corev1.ConditionUnknown {
+	if getSucceededCondition(buildRun).Status == corev1.ConditionUnknown {
 		Logf("Skipping validation of service account deletion because build run did not end.")
 		return
 	}